@@ -0,0 +1,70 @@
+// Ring buffer
+// Copyright (C) 2025  Kevin Z <zyxkad@gmail.com>
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package ringbuf
+
+import (
+	"io"
+)
+
+// ByteBuffer is a fixed size byte ring buffer which implements io.Reader and io.Writer
+type ByteBuffer struct {
+	buf *RingBuffer[byte]
+}
+
+var (
+	_ io.Reader = (*ByteBuffer)(nil)
+	_ io.Writer = (*ByteBuffer)(nil)
+)
+
+func NewByteBuffer(size int) *ByteBuffer {
+	return &ByteBuffer{
+		buf: NewRingBuffer[byte](size),
+	}
+}
+
+// Write appends p to the buffer, it never fails
+// The earliest bytes will be overwritten if there is no space avaliable
+func (b *ByteBuffer) Write(p []byte) (int, error) {
+	b.buf.PushSlice(p)
+	return len(p), nil
+}
+
+// Read removes the earliest bytes from the buffer and copies them into p
+// It returns io.EOF if the buffer is empty and len(p) is not zero
+func (b *ByteBuffer) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if b.buf.IsEmpty() {
+		return 0, io.EOF
+	}
+	return b.buf.PollN(p), nil
+}
+
+// Len returns the number of unread bytes in the buffer
+func (b *ByteBuffer) Len() int {
+	return b.buf.Len()
+}
+
+// Cap returns the total space of the buffer
+func (b *ByteBuffer) Cap() int {
+	return b.buf.Cap()
+}
+
+// Reset discards all unread bytes
+func (b *ByteBuffer) Reset() {
+	b.buf.Clear()
+}
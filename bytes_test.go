@@ -0,0 +1,67 @@
+// Ring buffer
+// Copyright (C) 2025  Kevin Z <zyxkad@gmail.com>
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package ringbuf_test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	. "github.com/kmcsr/go-ringbuf"
+)
+
+func TestByteBuffer(t *testing.T) {
+	b := NewByteBuffer(8)
+	if n, err := b.Read(make([]byte, 4)); n != 0 || err != io.EOF {
+		t.Errorf("Expect 0, EOF when read empty buffer, got %d, %v", n, err)
+	}
+	if n, err := b.Read(nil); n != 0 || err != nil {
+		t.Errorf("Expect 0, nil when read into empty slice, got %d, %v", n, err)
+	}
+	if n, err := b.Write([]byte("hello")); n != 5 || err != nil {
+		t.Errorf("Expect 5, nil when write, got %d, %v", n, err)
+	}
+	p := make([]byte, 3)
+	if n, err := b.Read(p); n != 3 || err != nil || string(p) != "hel" {
+		t.Errorf("Expect %q, got %q, %d, %v", "hel", p[:n], n, err)
+	}
+	b.Write([]byte(", world"))
+	// overwritten: "lo, world" -> "o, world"
+	if got := b.Len(); got != 8 {
+		t.Errorf("Expect 8 for length, got %d", got)
+	}
+	var out bytes.Buffer
+	if n, err := io.Copy(&out, b); n != 8 || err != nil || out.String() != "o, world" {
+		t.Errorf("Expect %q, got %q, %d, %v", "o, world", out.String(), n, err)
+	}
+
+	if _, err := io.Copy(b, strings.NewReader("ring")); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	data, err := io.ReadAll(b)
+	if err != nil || string(data) != "ring" {
+		t.Errorf("Expect %q, got %q, %v", "ring", data, err)
+	}
+	b.Write([]byte("abc"))
+	b.Reset()
+	if got := b.Len(); got != 0 {
+		t.Errorf("Expect 0 for length after reset, got %d", got)
+	}
+	if got := b.Cap(); got != 8 {
+		t.Errorf("Expect 8 for capacity, got %d", got)
+	}
+}
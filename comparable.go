@@ -0,0 +1,366 @@
+// Ring buffer
+// Copyright (C) 2025  Kevin Z <zyxkad@gmail.com>
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package ringbuf
+
+import (
+	"encoding/binary"
+	"hash/maphash"
+	"math"
+	"slices"
+)
+
+// IndexOf returns the logical index of the first element which equals to v, or -1 if there is none
+func IndexOf[T comparable](r *RingBuffer[T], v T) int {
+	i, n := -1, 0
+	r.ForEach(func(e T) bool {
+		if e == v {
+			i = n
+			return false
+		}
+		n++
+		return true
+	})
+	return i
+}
+
+// Contains reports whether v is in the buffer
+func Contains[T comparable](r *RingBuffer[T], v T) bool {
+	return IndexOf(r, v) >= 0
+}
+
+// Distance returns the absolute difference between the logical indexes of the first occurrences of a and b
+// ok will be false if either a or b is not in the buffer
+func Distance[T comparable](r *RingBuffer[T], a, b T) (int, bool) {
+	i := IndexOf(r, a)
+	if i < 0 {
+		return 0, false
+	}
+	j := IndexOf(r, b)
+	if j < 0 {
+		return 0, false
+	}
+	if i > j {
+		return i - j, true
+	}
+	return j - i, true
+}
+
+// FrequencySorted returns the distinct values in the buffer with their counts,
+// sorted by count in descending order
+// Values with the same count are ordered by their first occurrence
+func FrequencySorted[T comparable](r *RingBuffer[T]) []struct {
+	Value T
+	Count int
+} {
+	res := make([]struct {
+		Value T
+		Count int
+	}, 0)
+	indexes := make(map[T]int)
+	r.ForEach(func(v T) bool {
+		if i, ok := indexes[v]; ok {
+			res[i].Count++
+		} else {
+			indexes[v] = len(res)
+			res = append(res, struct {
+				Value T
+				Count int
+			}{Value: v, Count: 1})
+		}
+		return true
+	})
+	slices.SortStableFunc(res, func(a, b struct {
+		Value T
+		Count int
+	}) int {
+		return b.Count - a.Count
+	})
+	return res
+}
+
+// ContainsSeq returns the logical index where a contiguous subsequence equal to pattern begins
+// An empty pattern always matches at index 0
+func ContainsSeq[T comparable](r *RingBuffer[T], pattern []T) (startIndex int, found bool) {
+	n := r.Len()
+	for s := 0; s+len(pattern) <= n; s++ {
+		matched := true
+		for k, v := range pattern {
+			if r.Get(s+k) != v {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return s, true
+		}
+	}
+	return -1, false
+}
+
+// RunLengthEncode returns the runs of consecutive equal elements with their lengths, from first to last
+func RunLengthEncode[T comparable](r *RingBuffer[T]) []struct {
+	Value T
+	Count int
+} {
+	res := make([]struct {
+		Value T
+		Count int
+	}, 0)
+	r.ForEach(func(v T) bool {
+		if n := len(res); n > 0 && res[n-1].Value == v {
+			res[n-1].Count++
+		} else {
+			res = append(res, struct {
+				Value T
+				Count int
+			}{Value: v, Count: 1})
+		}
+		return true
+	})
+	return res
+}
+
+// IsRotation reports whether b's elements are a cyclic rotation of a's elements
+func IsRotation[T comparable](a, b *RingBuffer[T]) bool {
+	n := a.Len()
+	if n != b.Len() {
+		return false
+	}
+	if n == 0 {
+		return true
+	}
+	doubled := make([]T, 0, n*2)
+	a.ForEach(func(v T) bool {
+		doubled = append(doubled, v)
+		return true
+	})
+	doubled = append(doubled, doubled...)
+	for s := range n {
+		matched := true
+		for k := range n {
+			if doubled[s+k] != b.Get(k) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// Entropy returns the Shannon entropy in bits of the value distribution in the buffer
+// It returns 0 for an empty buffer or a buffer with only one distinct value
+func Entropy[T comparable](r *RingBuffer[T]) float64 {
+	counts := make(map[T]int)
+	r.ForEach(func(v T) bool {
+		counts[v]++
+		return true
+	})
+	if len(counts) <= 1 {
+		return 0
+	}
+	n := float64(r.Len())
+	var e float64
+	for _, c := range counts {
+		p := float64(c) / n
+		e -= p * math.Log2(p)
+	}
+	return e
+}
+
+// FirstChange returns the first logical index k where Get(k) != Get(k-1)
+// ok will be false if all elements are equal or there are fewer than 2 elements
+func FirstChange[T comparable](r *RingBuffer[T]) (index int, ok bool) {
+	k := 0
+	var prev T
+	r.ForEach(func(v T) bool {
+		if k > 0 && v != prev {
+			ok = true
+			return false
+		}
+		prev = v
+		k++
+		return true
+	})
+	if !ok {
+		return -1, false
+	}
+	return k, true
+}
+
+// AllUnique reports whether every element in the buffer is distinct
+func AllUnique[T comparable](r *RingBuffer[T]) bool {
+	seen := make(map[T]struct{}, r.Len())
+	unique := true
+	r.ForEach(func(v T) bool {
+		if _, ok := seen[v]; ok {
+			unique = false
+			return false
+		}
+		seen[v] = struct{}{}
+		return true
+	})
+	return unique
+}
+
+// contentHashSeed is the seed used by ContentHash
+var contentHashSeed = maphash.MakeSeed()
+
+// contentHashOf hashes v for ContentHash
+// float32 and float64 are hashed by their bits with -0 treated as +0, so identical NaN are hashed identically
+func contentHashOf[T comparable](v T) uint64 {
+	switch f := any(v).(type) {
+	case float64:
+		if f == 0 {
+			f = 0
+		}
+		return maphash.Comparable(contentHashSeed, math.Float64bits(f))
+	case float32:
+		if f == 0 {
+			f = 0
+		}
+		return maphash.Comparable(contentHashSeed, math.Float32bits(f))
+	}
+	return maphash.Comparable(contentHashSeed, v)
+}
+
+// ContentHash returns an order sensitive hash of the elements, from first to last
+// Buffers whose elements are equal in the same order always have the same hash
+// Elements of type float32 or float64 are compared by their bits instead, with -0 equal to +0,
+// so an unchanged buffer holding NaN keeps its hash
+// NaN nested in other types, such as named float types, structs or interfaces, is hashed randomly,
+// so a buffer holding it never has the same hash twice
+// The seed is chosen randomly at start, so the hashes are only comparable within the same process
+// It panics if an element is an interface holding an incomparable value, as == does
+func ContentHash[T comparable](r *RingBuffer[T]) uint64 {
+	var h maphash.Hash
+	h.SetSeed(contentHashSeed)
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], uint64(r.Len()))
+	h.Write(buf[:])
+	r.ForEach(func(v T) bool {
+		binary.LittleEndian.PutUint64(buf[:], contentHashOf(v))
+		h.Write(buf[:])
+		return true
+	})
+	return h.Sum64()
+}
+
+// Diff2 returns the elements in b but not in a as added, and the elements in a but not in b as removed
+// Elements are compared as multisets, so an element appears in the result as many times as its count differs
+// The results are in the logical order of b and a respectively
+func Diff2[T comparable](a, b *RingBuffer[T]) (added, removed []T) {
+	counts := make(map[T]int)
+	a.ForEach(func(v T) bool {
+		counts[v]++
+		return true
+	})
+	b.ForEach(func(v T) bool {
+		counts[v]--
+		return true
+	})
+	b.ForEach(func(v T) bool {
+		if counts[v] < 0 {
+			counts[v]++
+			added = append(added, v)
+		}
+		return true
+	})
+	a.ForEach(func(v T) bool {
+		if counts[v] > 0 {
+			counts[v]--
+			removed = append(removed, v)
+		}
+		return true
+	})
+	return
+}
+
+// IsPalindrome reports whether the elements read the same from first to last and from last to first
+// An empty or single element buffer is a palindrome
+func IsPalindrome[T comparable](r *RingBuffer[T]) bool {
+	for a, b := 0, r.Len()-1; a < b; a, b = a+1, b-1 {
+		if r.Get(a) != r.Get(b) {
+			return false
+		}
+	}
+	return true
+}
+
+// GroupRuns returns the runs of consecutive elements which have the same key, from first to last
+func GroupRuns[T any, K comparable](r *RingBuffer[T], keyFn func(T) K) [][]T {
+	var (
+		res  [][]T
+		prev K
+	)
+	r.ForEach(func(v T) bool {
+		key := keyFn(v)
+		if n := len(res); n > 0 && key == prev {
+			res[n-1] = append(res[n-1], v)
+		} else {
+			res = append(res, []T{v})
+			prev = key
+		}
+		return true
+	})
+	return res
+}
+
+// PushIfChanged pushes v only if the buffer is empty or v is not equal to the last pushed element
+// It returns whether v is pushed
+func PushIfChanged[T comparable](r *RingBuffer[T], v T) bool {
+	if last, ok := r.PeekBack(); ok && last == v {
+		return false
+	}
+	r.Push(v)
+	return true
+}
+
+// CompactNonZero removes all elements which equal to the zero value of T, and keeps the order of the rest
+func CompactNonZero[T comparable](r *RingBuffer[T]) {
+	var zero T
+	r.RemoveFunc(func(v T) bool {
+		return v != zero
+	})
+}
+
+// HammingDistance returns the number of logical indexes where the elements of a and b differ
+// ok will be false if the lengths of a and b differ
+func HammingDistance[T comparable](a, b *RingBuffer[T]) (int, bool) {
+	n := a.Len()
+	if n != b.Len() {
+		return 0, false
+	}
+	d := 0
+	for k := range n {
+		if a.Get(k) != b.Get(k) {
+			d++
+		}
+	}
+	return d, true
+}
+
+// RunStats returns the number of runs of consecutive equal elements, and the mean length of them
+// It returns 0, 0 for an empty buffer
+func RunStats[T comparable](r *RingBuffer[T]) (runCount int, meanRunLength float64) {
+	runs := RunLengthEncode(r)
+	if len(runs) == 0 {
+		return 0, 0
+	}
+	return len(runs), float64(r.Len()) / float64(len(runs))
+}
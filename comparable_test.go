@@ -0,0 +1,474 @@
+// Ring buffer
+// Copyright (C) 2025  Kevin Z <zyxkad@gmail.com>
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package ringbuf_test
+
+import (
+	"math"
+	"testing"
+
+	. "github.com/kmcsr/go-ringbuf"
+)
+
+func TestIndexOf(t *testing.T) {
+	rb := NewRingBuffer[int](4)
+	if got := IndexOf(rb, 0); got != -1 {
+		t.Errorf("Expect -1 for empty buffer, got %d", got)
+	}
+	if Contains(rb, 0) {
+		t.Errorf("Expect empty buffer contains nothing")
+	}
+	for _, v := range []int{7, 1, 2, 3, 2} {
+		rb.Push(v)
+	}
+	// buffer: 1 2 3 2
+	for _, c := range []struct{ v, index int }{{1, 0}, {2, 1}, {3, 2}, {7, -1}, {0, -1}} {
+		if got := IndexOf(rb, c.v); got != c.index {
+			t.Errorf("Expect %d for index of %d, got %d", c.index, c.v, got)
+		}
+		if got := Contains(rb, c.v); got != (c.index >= 0) {
+			t.Errorf("Expect %v for contains %d, got %v", c.index >= 0, c.v, got)
+		}
+	}
+}
+
+func TestDistance(t *testing.T) {
+	rb := NewRingBuffer[int](4)
+	for _, v := range []int{9, 1, 2, 3, 1, 4} {
+		rb.Push(v)
+	}
+	// buffer: 2 3 1 4
+	if got, ok := Distance(rb, 2, 4); !ok || got != 3 {
+		t.Errorf("Expect 3 for distance between 2 and 4, got %d, %v", got, ok)
+	}
+	if got, ok := Distance(rb, 4, 3); !ok || got != 2 {
+		t.Errorf("Expect 2 for distance between 4 and 3, got %d, %v", got, ok)
+	}
+	if got, ok := Distance(rb, 1, 1); !ok || got != 0 {
+		t.Errorf("Expect 0 for distance between 1 and 1, got %d, %v", got, ok)
+	}
+	if _, ok := Distance(rb, 2, 9); ok {
+		t.Errorf("Expect not ok when an element is absent")
+	}
+	if _, ok := Distance(rb, 9, 2); ok {
+		t.Errorf("Expect not ok when an element is absent")
+	}
+}
+
+func TestFrequencySorted(t *testing.T) {
+	rb := NewRingBuffer[string](8)
+	for _, v := range []string{"x", "b", "a", "c", "a", "b", "a", "d", "c"} {
+		rb.Push(v)
+	}
+	// buffer: b a c a b a d c
+	res := FrequencySorted(rb)
+	values := []string{"a", "b", "c", "d"}
+	counts := []int{3, 2, 2, 1}
+	if len(res) != len(values) {
+		t.Fatalf("Expect %d distinct values, got %d", len(values), len(res))
+	}
+	for i, e := range res {
+		if e.Value != values[i] || e.Count != counts[i] {
+			t.Errorf("Expect %s:%d at i %d, got %s:%d", values[i], counts[i], i, e.Value, e.Count)
+		}
+	}
+	if res := FrequencySorted(NewRingBuffer[string](1)); len(res) != 0 {
+		t.Errorf("Expect no values for empty buffer, got %v", res)
+	}
+}
+
+func TestContainsSeq(t *testing.T) {
+	rb := NewRingBuffer[int](6)
+	for i := range 9 {
+		rb.Push(i)
+	}
+	// buffer: 3 4 5 6 7 8, with 6 at the wrap boundary
+	expect := func(pattern []int, index int, found bool) {
+		if i, ok := ContainsSeq(rb, pattern); ok != found || (found && i != index) {
+			t.Errorf("Expect %d, %v for pattern %v, got %d, %v", index, found, pattern, i, ok)
+		}
+	}
+	expect([]int{3, 4}, 0, true)
+	expect([]int{5, 6}, 2, true)
+	expect([]int{5, 6, 7}, 2, true)
+	expect([]int{7, 8}, 4, true)
+	expect([]int{3, 4, 5, 6, 7, 8}, 0, true)
+	expect([]int{}, 0, true)
+	expect([]int{4, 6}, 0, false)
+	expect([]int{8, 9}, 0, false)
+	expect([]int{3, 4, 5, 6, 7, 8, 9}, 0, false)
+}
+
+func TestRunLengthEncode(t *testing.T) {
+	expect := func(input []int, values []int, counts []int) {
+		rb := NewRingBuffer[int](5)
+		for _, v := range input {
+			rb.Push(v)
+		}
+		res := RunLengthEncode(rb)
+		if len(res) != len(values) {
+			t.Errorf("Expect %d runs for %v, got %v", len(values), input, res)
+			return
+		}
+		for i, e := range res {
+			if e.Value != values[i] || e.Count != counts[i] {
+				t.Errorf("Expect %d:%d at i %d for %v, got %d:%d", values[i], counts[i], i, input, e.Value, e.Count)
+			}
+		}
+	}
+	expect([]int{}, []int{}, []int{})
+	expect([]int{1, 2, 1, 2, 1}, []int{1, 2, 1, 2, 1}, []int{1, 1, 1, 1, 1})
+	expect([]int{7, 7, 7, 7, 7, 7, 7}, []int{7}, []int{5})
+	expect([]int{1, 2, 3, 4, 5, 6}, []int{2, 3, 4, 5, 6}, []int{1, 1, 1, 1, 1})
+	expect([]int{0, 0, 0, 1, 1, 2, 2, 2}, []int{1, 2}, []int{2, 3})
+}
+
+func TestIsRotation(t *testing.T) {
+	newBuf := func(size int, vs ...int) *RingBuffer[int] {
+		rb := NewRingBuffer[int](size)
+		for _, v := range vs {
+			rb.Push(v)
+		}
+		return rb
+	}
+	a := newBuf(4, 0, 1, 2, 3, 4)
+	if !IsRotation(a, newBuf(4, 3, 4, 1, 2)) {
+		t.Errorf("Expect true for a rotation")
+	}
+	if !IsRotation(a, newBuf(5, 1, 2, 3, 4)) {
+		t.Errorf("Expect true for an identical sequence")
+	}
+	if !IsRotation(a, newBuf(6, 4, 1, 2, 3)) {
+		t.Errorf("Expect true for a rotation")
+	}
+	if IsRotation(a, newBuf(4, 4, 3, 2, 1)) {
+		t.Errorf("Expect false for a reversed sequence")
+	}
+	if !IsRotation(newBuf(3, 1, 2, 1), newBuf(3, 1, 2, 1)) {
+		t.Errorf("Expect true for a reversed palindrome")
+	}
+	if IsRotation(a, newBuf(4, 2, 3, 4)) {
+		t.Errorf("Expect false for different lengths")
+	}
+	if IsRotation(a, newBuf(4, 1, 2, 4, 3)) {
+		t.Errorf("Expect false for a different cyclic order")
+	}
+	if !IsRotation(newBuf(1), newBuf(2)) {
+		t.Errorf("Expect true for empty buffers")
+	}
+}
+
+func TestEntropy(t *testing.T) {
+	rb := NewRingBuffer[string](4)
+	if got := Entropy(rb); got != 0 {
+		t.Errorf("Expect 0 for empty buffer, got %v", got)
+	}
+	rb.Push("a")
+	rb.Push("a")
+	if got := Entropy(rb); got != 0 {
+		t.Errorf("Expect 0 for single value buffer, got %v", got)
+	}
+	rb.Push("b")
+	rb.Push("c")
+	// p: 1/2 1/4 1/4
+	if got := Entropy(rb); math.Abs(got-1.5) > 1e-9 {
+		t.Errorf("Expect 1.5 for entropy, got %v", got)
+	}
+	rb.Push("d")
+	rb.Push("a")
+	// buffer: b c d a
+	if got := Entropy(rb); math.Abs(got-2) > 1e-9 {
+		t.Errorf("Expect 2 for entropy, got %v", got)
+	}
+}
+
+func TestFirstChange(t *testing.T) {
+	rb := NewRingBuffer[int](4)
+	if _, ok := FirstChange(rb); ok {
+		t.Errorf("Expect not ok for empty buffer")
+	}
+	rb.Push(1)
+	if _, ok := FirstChange(rb); ok {
+		t.Errorf("Expect not ok for single element buffer")
+	}
+	rb.Push(1)
+	rb.Push(1)
+	rb.Push(1)
+	rb.Push(1)
+	if _, ok := FirstChange(rb); ok {
+		t.Errorf("Expect not ok for constant buffer")
+	}
+	rb.Push(2)
+	rb.Push(2)
+	// buffer: 1 1 2 2
+	if got, ok := FirstChange(rb); !ok || got != 2 {
+		t.Errorf("Expect 2 for first change, got %d, %v", got, ok)
+	}
+}
+
+func TestAllUnique(t *testing.T) {
+	rb := NewRingBuffer[int](4)
+	if !AllUnique(rb) {
+		t.Errorf("Expect true for empty buffer")
+	}
+	for _, v := range []int{1, 1, 2, 3, 4} {
+		rb.Push(v)
+	}
+	if !AllUnique(rb) {
+		t.Errorf("Expect true for all unique buffer")
+	}
+	rb.Push(2)
+	// buffer: 2 3 4 2
+	if AllUnique(rb) {
+		t.Errorf("Expect false for buffer with duplicate")
+	}
+}
+
+func TestContentHash(t *testing.T) {
+	newBuf := func(vs ...string) *RingBuffer[string] {
+		rb := NewRingBuffer[string](3)
+		for _, v := range vs {
+			rb.Push(v)
+		}
+		return rb
+	}
+	base := newBuf("x", "a", "b", "c")
+	if ContentHash(base) != ContentHash(newBuf("a", "b", "c")) {
+		t.Errorf("Expect the same hash for the same contents")
+	}
+	for _, other := range []*RingBuffer[string]{
+		newBuf("a", "b", "d"),
+		newBuf("z", "b", "c"),
+		newBuf("a", "x", "c"),
+		newBuf("c", "a", "b"),
+		newBuf("a", "b"),
+		newBuf("ab", "c"),
+		newBuf("a", "bc", ""),
+	} {
+		if ContentHash(base) == ContentHash(other) {
+			t.Errorf("Expect different hash for %v", other.Sample(3))
+		}
+	}
+	if allocs := testing.AllocsPerRun(100, func() { ContentHash(base) }); allocs != 0 {
+		t.Errorf("Expect no allocation, got %v", allocs)
+	}
+
+	zeros := NewRingBuffer[float64](2)
+	zeros.Push(1)
+	zeros.Push(0)
+	negZeros := NewRingBuffer[float64](2)
+	negZeros.Push(1)
+	negZeros.Push(math.Copysign(0, -1))
+	if ContentHash(zeros) != ContentHash(negZeros) {
+		t.Errorf("Expect the same hash for +0 and -0")
+	}
+	zeros.Push(math.NaN())
+	if ContentHash(zeros) != ContentHash(zeros) {
+		t.Errorf("Expect the same hash for an unchanged buffer holding NaN")
+	}
+	if ContentHash(zeros) == ContentHash(negZeros) {
+		t.Errorf("Expect different hash after pushing NaN")
+	}
+	if allocs := testing.AllocsPerRun(100, func() { ContentHash(zeros) }); allocs != 0 {
+		t.Errorf("Expect no allocation for floats, got %v", allocs)
+	}
+	float32s := NewRingBuffer[float32](2)
+	float32s.Push(float32(math.Copysign(0, -1)))
+	float32s.Push(float32(math.NaN()))
+	float32Zeros := NewRingBuffer[float32](2)
+	float32Zeros.Push(0)
+	float32Zeros.Push(float32(math.NaN()))
+	if ContentHash(float32s) != ContentHash(float32Zeros) {
+		t.Errorf("Expect the same hash for float32 -0 and +0 with NaN")
+	}
+}
+
+func TestDiff2(t *testing.T) {
+	newBuf := func(vs ...int) *RingBuffer[int] {
+		rb := NewRingBuffer[int](5)
+		for _, v := range vs {
+			rb.Push(v)
+		}
+		return rb
+	}
+	equal := func(a, b []int) bool {
+		if len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if a[i] != b[i] {
+				return false
+			}
+		}
+		return true
+	}
+	for _, c := range []struct {
+		a, b           *RingBuffer[int]
+		added, removed []int
+	}{
+		{newBuf(1, 2, 3), newBuf(2, 3, 4), []int{4}, []int{1}},
+		{newBuf(1, 2, 2, 3), newBuf(2, 3, 3, 5), []int{3, 5}, []int{1, 2}},
+		{newBuf(1, 2), newBuf(3, 4), []int{3, 4}, []int{1, 2}},
+		{newBuf(0, 1, 2, 3, 4, 5), newBuf(5, 4, 3, 2, 1), nil, nil},
+		{newBuf(), newBuf(), nil, nil},
+	} {
+		added, removed := Diff2(c.a, c.b)
+		if !equal(added, c.added) || !equal(removed, c.removed) {
+			t.Errorf("Expect %v, %v, got %v, %v", c.added, c.removed, added, removed)
+		}
+	}
+}
+
+func TestIsPalindrome(t *testing.T) {
+	expect := func(input string, drop int, want bool) {
+		rb := NewRingBuffer[rune](5)
+		for _, v := range input {
+			rb.Push(v)
+		}
+		for range drop {
+			rb.Poll()
+		}
+		if got := IsPalindrome(rb); got != want {
+			t.Errorf("Expect %v for %q with %d dropped, got %v", want, input, drop, got)
+		}
+	}
+	expect("", 0, true)
+	expect("a", 0, true)
+	expect("abba", 0, true)
+	expect("abcba", 0, true)
+	expect("xyzabba", 1, true)
+	expect("xyzabcba", 0, true)
+	expect("abca", 0, false)
+	expect("abcbax", 0, false)
+	expect("xyzabca", 1, false)
+	expect("xyzabcab", 0, false)
+}
+
+func TestGroupRuns(t *testing.T) {
+	expect := func(input []int, want [][]int) {
+		rb := NewRingBuffer[int](6)
+		for _, v := range input {
+			rb.Push(v)
+		}
+		got := GroupRuns(rb, func(v int) int { return v / 10 })
+		if len(got) != len(want) {
+			t.Errorf("Expect %v for %v, got %v", want, input, got)
+			return
+		}
+		for i, run := range want {
+			if len(got[i]) != len(run) {
+				t.Errorf("Expect %v for %v, got %v", want, input, got)
+				return
+			}
+			for k, v := range run {
+				if got[i][k] != v {
+					t.Errorf("Expect %v for %v, got %v", want, input, got)
+					return
+				}
+			}
+		}
+	}
+	expect([]int{}, [][]int{})
+	expect([]int{1, 11, 2, 12}, [][]int{{1}, {11}, {2}, {12}})
+	expect([]int{1, 2, 11, 12, 13, 3}, [][]int{{1, 2}, {11, 12, 13}, {3}})
+	expect([]int{0, 0, 1, 2, 21, 22, 3, 4}, [][]int{{1, 2}, {21, 22}, {3, 4}})
+}
+
+func TestPushIfChanged(t *testing.T) {
+	rb := NewRingBuffer[int](4)
+	for _, c := range []struct {
+		v    int
+		want bool
+	}{{1, true}, {1, false}, {1, false}, {2, true}, {1, true}, {2, true}, {2, false}, {3, true}} {
+		if got := PushIfChanged(rb, c.v); got != c.want {
+			t.Errorf("Expect %v when push %d, got %v", c.want, c.v, got)
+		}
+	}
+	for i, v := range []int{2, 1, 2, 3} {
+		if got := rb.Get(i); got != v {
+			t.Errorf("Expect %d at i %d, got %d", v, i, got)
+		}
+	}
+}
+
+func TestCompactNonZero(t *testing.T) {
+	rb := NewRingBuffer[int](6)
+	for _, v := range []int{1, 1, 0, 2, 0, 3, 0, 4} {
+		rb.Push(v)
+	}
+	// buffer: 0 2 0 3 0 4, wrapped
+	CompactNonZero(rb)
+	want := []int{2, 3, 4}
+	if got := rb.Len(); got != len(want) {
+		t.Fatalf("Expect %d for length, got %d", len(want), got)
+	}
+	for i, v := range want {
+		if got := rb.Get(i); got != v {
+			t.Errorf("Expect %d at i %d, got %d", v, i, got)
+		}
+	}
+	rb.Push(5)
+	if got, ok := rb.PeekBack(); !ok || got != 5 {
+		t.Errorf("Expect %d when peek back, got %d", 5, got)
+	}
+	CompactNonZero(rb)
+	if got := rb.Len(); got != 4 {
+		t.Errorf("Expect 4 for length, got %d", got)
+	}
+	zeros := NewRingBufferFromSlice(3, []string{"", "", ""})
+	CompactNonZero(zeros)
+	if !zeros.IsEmpty() {
+		t.Errorf("Expect empty buffer, got %v", zeros.ToSlice())
+	}
+}
+
+func TestHammingDistance(t *testing.T) {
+	a := NewRingBufferFromSlice(4, []rune("abcd"))
+	b := NewRingBuffer[rune](4)
+	for _, v := range "xxabcd" {
+		b.Push(v)
+	}
+	if got, ok := HammingDistance(a, b); !ok || got != 0 {
+		t.Errorf("Expect 0 for identical buffers, got %d, %v", got, ok)
+	}
+	b.Set(1, 'z')
+	b.Set(3, 'z')
+	if got, ok := HammingDistance(a, b); !ok || got != 2 {
+		t.Errorf("Expect 2 for distance, got %d, %v", got, ok)
+	}
+	if got, ok := HammingDistance(a, NewRingBufferFromSlice(4, []rune("wxyz"))); !ok || got != 4 {
+		t.Errorf("Expect 4 for fully different buffers, got %d, %v", got, ok)
+	}
+	if _, ok := HammingDistance(a, NewRingBufferFromSlice(4, []rune("abc"))); ok {
+		t.Errorf("Expect not ok for length mismatch")
+	}
+}
+
+func TestRunStats(t *testing.T) {
+	expect := func(input string, count int, mean float64) {
+		rb := NewRingBuffer[rune](6)
+		for _, v := range input {
+			rb.Push(v)
+		}
+		if c, m := RunStats(rb); c != count || m != mean {
+			t.Errorf("Expect %d, %v for %q, got %d, %v", count, mean, input, c, m)
+		}
+	}
+	expect("", 0, 0)
+	expect("aaaaaaaa", 1, 6)
+	expect("abcdef", 6, 1)
+	expect("xxaabbbc", 3, 2)
+	expect("aabbbb", 2, 3)
+}
@@ -0,0 +1,119 @@
+// Ring buffer
+// Copyright (C) 2025  Kevin Z <zyxkad@gmail.com>
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package ringbuf
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/csv"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+)
+
+var (
+	_ encoding.BinaryMarshaler   = (*RingBuffer[int])(nil)
+	_ encoding.BinaryUnmarshaler = (*RingBuffer[int])(nil)
+	_ json.Marshaler             = (*RingBuffer[int])(nil)
+	_ json.Unmarshaler           = (*RingBuffer[int])(nil)
+)
+
+// ringBufferData is the serialized form of a ring buffer
+type ringBufferData[T any] struct {
+	Cap   int
+	Elems []T
+}
+
+func (d *ringBufferData[T]) load(r *RingBuffer[T]) error {
+	if d.Cap < 1 {
+		return fmt.Errorf("invalid ring buffer capacity %d", d.Cap)
+	}
+	if len(d.Elems) > d.Cap {
+		return fmt.Errorf("ring buffer has %d elements which exceed its capacity %d", len(d.Elems), d.Cap)
+	}
+	if len(r.buf) != d.Cap {
+		r.buf = make([]T, d.Cap)
+	}
+	r.replace(d.Elems)
+	r.overflowed = false
+	return nil
+}
+
+// WriteCSVRow writes the elements as a single CSV record, from first to last
+// Each element is formatted with fmt.Sprint
+// Nothing will be written if the buffer is empty
+func (r *RingBuffer[T]) WriteCSVRow(w *csv.Writer) error {
+	if !r.hasElem {
+		return nil
+	}
+	record := make([]string, 0, r.Len())
+	r.ForEach(func(v T) bool {
+		record = append(record, fmt.Sprint(v))
+		return true
+	})
+	return w.Write(record)
+}
+
+// MarshalBinary encodes the capacity and the elements from first to last with encoding/gob
+// T must be encodable by encoding/gob
+func (r *RingBuffer[T]) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(ringBufferData[T]{
+		Cap:   len(r.buf),
+		Elems: r.ToSlice(),
+	}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary decodes data encoded by MarshalBinary
+// The buffer's capacity and elements are replaced by the decoded ones
+func (r *RingBuffer[T]) UnmarshalBinary(data []byte) error {
+	var d ringBufferData[T]
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&d); err != nil {
+		return err
+	}
+	return d.load(r)
+}
+
+// MarshalJSON encodes the elements from first to last as a JSON array
+// The capacity is not encoded, and an empty buffer is encoded as []
+func (r *RingBuffer[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.ToSlice())
+}
+
+// UnmarshalJSON decodes a JSON array into the buffer, and keeps the buffer's capacity
+// Only the last Cap() elements are kept if the array is longer than the capacity
+// If the buffer is a zero value, its capacity will be the length of the array, or 1 if the array is empty
+func (r *RingBuffer[T]) UnmarshalJSON(data []byte) error {
+	var elems []T
+	if err := json.Unmarshal(data, &elems); err != nil {
+		return err
+	}
+	size := len(r.buf)
+	if size == 0 {
+		size = max(len(elems), 1)
+	}
+	if len(elems) > size {
+		elems = elems[len(elems)-size:]
+	}
+	d := ringBufferData[T]{
+		Cap:   size,
+		Elems: elems,
+	}
+	return d.load(r)
+}
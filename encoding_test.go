@@ -0,0 +1,192 @@
+// Ring buffer
+// Copyright (C) 2025  Kevin Z <zyxkad@gmail.com>
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package ringbuf_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/gob"
+	"encoding/json"
+	"testing"
+
+	. "github.com/kmcsr/go-ringbuf"
+)
+
+func TestRingBufferWriteCSVRow(t *testing.T) {
+	rb := NewRingBuffer[string](3)
+	for _, v := range []string{"x", "a", "b,c", `"d"`} {
+		rb.Push(v)
+	}
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := rb.WriteCSVRow(w); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := NewRingBuffer[string](1).WriteCSVRow(w); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	w.Flush()
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("Expect 1 record, got %d", len(records))
+	}
+	if len(records[0]) != rb.Len() {
+		t.Fatalf("Expect %d fields, got %d", rb.Len(), len(records[0]))
+	}
+	for i, v := range records[0] {
+		if want := rb.Get(i); v != want {
+			t.Errorf("Expect %q at i %d, got %q", want, i, v)
+		}
+	}
+
+	nums := NewRingBuffer[float64](2)
+	nums.Push(1.5)
+	nums.Push(-2)
+	buf.Reset()
+	w = csv.NewWriter(&buf)
+	if err := nums.WriteCSVRow(w); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	w.Flush()
+	if got, want := buf.String(), "1.5,-2\n"; got != want {
+		t.Errorf("Expect %q, got %q", want, got)
+	}
+}
+
+func TestRingBufferMarshalBinary(t *testing.T) {
+	for _, pushes := range []int{0, 1, 3, 5, 9} {
+		rb := NewRingBuffer[uint64](5)
+		for i := range pushes {
+			rb.Push(uint64(i * 100))
+		}
+		rb.Poll()
+		data, err := rb.MarshalBinary()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		var got RingBuffer[uint64]
+		if err := got.UnmarshalBinary(data); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got.Cap() != rb.Cap() || got.Len() != rb.Len() {
+			t.Errorf("Expect %d, %d for length and capacity, got %d, %d", rb.Len(), rb.Cap(), got.Len(), got.Cap())
+			continue
+		}
+		for i := range rb.Len() {
+			if g, v := got.Get(i), rb.Get(i); g != v {
+				t.Errorf("Expect %d at i %d, got %d", v, i, g)
+			}
+		}
+		got.Push(1)
+		if v, _ := got.PeekBack(); v != 1 {
+			t.Errorf("Expect %d when peek back, got %d", 1, v)
+		}
+	}
+
+	type point struct{ X, Y int }
+	rb := NewRingBufferFromSlice(3, []point{{1, 2}, {3, 4}})
+	data, err := rb.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	got := NewRingBuffer[point](1)
+	if err := got.UnmarshalBinary(data); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got.Cap() != 3 || got.Len() != 2 || got.Get(1) != (point{3, 4}) {
+		t.Errorf("Expect %v, got %v", rb.ToSlice(), got.ToSlice())
+	}
+	if err := got.UnmarshalBinary([]byte("invalid")); err == nil {
+		t.Errorf("Expect error for invalid data")
+	}
+	if got.Cap() != 3 || got.Len() != 2 {
+		t.Errorf("Expect buffer unchanged after error, got %v", got.ToSlice())
+	}
+
+	for _, corrupt := range []struct {
+		Cap   int
+		Elems []point
+	}{
+		{0, nil},
+		{-1, nil},
+		{1, []point{{1, 2}, {3, 4}}},
+	} {
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).Encode(corrupt); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if err := got.UnmarshalBinary(buf.Bytes()); err == nil {
+			t.Errorf("Expect error for corrupt data %v", corrupt)
+		}
+	}
+}
+
+func TestRingBufferJSON(t *testing.T) {
+	rb := NewRingBuffer[int](3)
+	expect := func(v any, want string) {
+		t.Helper()
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if string(data) != want {
+			t.Errorf("Expect %s, got %s", want, data)
+		}
+	}
+	expect(rb, "[]")
+	for i := range 5 {
+		rb.Push(i)
+	}
+	expect(rb, "[2,3,4]")
+	expect(struct {
+		Window *RingBuffer[int] `json:"window"`
+	}{rb}, `{"window":[2,3,4]}`)
+
+	got := NewRingBuffer[int](4)
+	got.Push(9)
+	if err := json.Unmarshal([]byte("[1,2,3]"), got); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got.Cap() != 4 || got.Len() != 3 || got.Get(0) != 1 || got.Get(2) != 3 {
+		t.Errorf("Expect [1 2 3] with capacity 4, got %v with capacity %d", got.ToSlice(), got.Cap())
+	}
+	if err := json.Unmarshal([]byte("[1,2,3,4,5,6]"), got); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got.Cap() != 4 || got.Len() != 4 || got.Get(0) != 3 || got.Get(3) != 6 {
+		t.Errorf("Expect [3 4 5 6] with capacity 4, got %v with capacity %d", got.ToSlice(), got.Cap())
+	}
+
+	var s struct {
+		Window RingBuffer[string] `json:"window"`
+	}
+	if err := json.Unmarshal([]byte(`{"window":["a","b"]}`), &s); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if s.Window.Cap() != 2 || s.Window.Len() != 2 || s.Window.Get(1) != "b" {
+		t.Errorf("Expect [a b] with capacity 2, got %v with capacity %d", s.Window.ToSlice(), s.Window.Cap())
+	}
+	s.Window.Push("c")
+	if s.Window.Get(0) != "b" {
+		t.Errorf("Expect %q at i 0, got %q", "b", s.Window.Get(0))
+	}
+	if err := json.Unmarshal([]byte(`{"a":1}`), got); err == nil {
+		t.Errorf("Expect error for invalid data")
+	}
+}
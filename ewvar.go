@@ -0,0 +1,70 @@
+// Ring buffer
+// Copyright (C) 2025  Kevin Z <zyxkad@gmail.com>
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package ringbuf
+
+// EWVar maintains the exponentially weighted mean and variance of a float64 stream,
+// and keeps the most recent raw samples in a ring buffer
+type EWVar struct {
+	decay    float64
+	mean     float64
+	variance float64
+	started  bool
+	samples  *RingBuffer[float64]
+}
+
+// NewEWVar creates an EWVar which keeps size recent samples
+// Each time a sample is added, the weight of the previous statistics is multiplied by decay
+// It will panic if decay is not in (0, 1)
+func NewEWVar(decay float64, size int) *EWVar {
+	if decay <= 0 || decay >= 1 {
+		panic("decay must be in (0, 1)")
+	}
+	return &EWVar{
+		decay:   decay,
+		samples: NewRingBuffer[float64](size),
+	}
+}
+
+// Add records a sample and updates the mean and variance
+func (e *EWVar) Add(v float64) {
+	e.samples.Push(v)
+	if !e.started {
+		e.mean = v
+		e.variance = 0
+		e.started = true
+		return
+	}
+	alpha := 1 - e.decay
+	diff := v - e.mean
+	incr := alpha * diff
+	e.mean += incr
+	e.variance = e.decay * (e.variance + diff*incr)
+}
+
+// Mean returns the exponentially weighted mean
+func (e *EWVar) Mean() float64 {
+	return e.mean
+}
+
+// Variance returns the exponentially weighted variance
+func (e *EWVar) Variance() float64 {
+	return e.variance
+}
+
+// Samples returns the most recent samples from first to last
+func (e *EWVar) Samples() []float64 {
+	return e.samples.ToSlice()
+}
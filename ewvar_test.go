@@ -0,0 +1,52 @@
+// Ring buffer
+// Copyright (C) 2025  Kevin Z <zyxkad@gmail.com>
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package ringbuf_test
+
+import (
+	"math"
+	"testing"
+
+	. "github.com/kmcsr/go-ringbuf"
+)
+
+func TestEWVar(t *testing.T) {
+	e := NewEWVar(0.5, 3)
+	if got := e.Variance(); got != 0 {
+		t.Errorf("Expect 0 for variance without samples, got %v", got)
+	}
+	e.Add(2)
+	if e.Mean() != 2 || e.Variance() != 0 {
+		t.Errorf("Expect 2, 0 for mean and variance, got %v, %v", e.Mean(), e.Variance())
+	}
+	e.Add(4)
+	// diff = 2, incr = 1, mean = 3, variance = 0.5 * (0 + 2*1)
+	if e.Mean() != 3 || e.Variance() != 1 {
+		t.Errorf("Expect 3, 1 for mean and variance, got %v, %v", e.Mean(), e.Variance())
+	}
+	for range 200 {
+		e.Add(10)
+	}
+	if math.Abs(e.Mean()-10) > 1e-9 {
+		t.Errorf("Expect mean converges to 10, got %v", e.Mean())
+	}
+	if e.Variance() > 1e-9 {
+		t.Errorf("Expect variance converges to 0, got %v", e.Variance())
+	}
+	samples := e.Samples()
+	if len(samples) != 3 || samples[0] != 10 || samples[2] != 10 {
+		t.Errorf("Expect the last 3 samples, got %v", samples)
+	}
+}
@@ -0,0 +1,266 @@
+// Ring buffer
+// Copyright (C) 2025  Kevin Z <zyxkad@gmail.com>
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package ringbuf
+
+import (
+	"slices"
+)
+
+// Number is a constraint that permits any integer or floating-point type
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// TrimmedMean returns the mean of the elements after discarding
+// the lowest and the highest trimFraction of them
+// ok will be false if the buffer is empty or all elements are trimmed
+// It will panic if trimFraction is not in [0, 0.5)
+func TrimmedMean[T Number](r *RingBuffer[T], trimFraction float64) (float64, bool) {
+	if trimFraction < 0 || trimFraction >= 0.5 {
+		panic("trim fraction must be in [0, 0.5)")
+	}
+	values := r.ToSlice()
+	slices.Sort(values)
+	k := int(float64(len(values)) * trimFraction)
+	values = values[k : len(values)-k]
+	if len(values) == 0 {
+		return 0, false
+	}
+	var sum float64
+	for _, v := range values {
+		sum += float64(v)
+	}
+	return sum / float64(len(values)), true
+}
+
+// DeltaEncode returns the first element followed by the differences between each element and its previous one
+// The result can be restored by DeltaDecode
+// Note: floating-point values may lose precision during the round trip
+func DeltaEncode[T Number](r *RingBuffer[T]) []T {
+	res := make([]T, 0, r.Len())
+	var prev T
+	r.ForEach(func(v T) bool {
+		res = append(res, v-prev)
+		prev = v
+		return true
+	})
+	return res
+}
+
+// DeltaDecode restores the values encoded by DeltaEncode
+func DeltaDecode[T Number](deltas []T) []T {
+	res := make([]T, len(deltas))
+	var prev T
+	for i, d := range deltas {
+		prev += d
+		res[i] = prev
+	}
+	return res
+}
+
+// WeightedSum returns the sum of Get(k) * weights[k] for each element
+// ok will be false if the length of weights does not match the buffer's length
+func WeightedSum[T Number](r *RingBuffer[T], weights []T) (T, bool) {
+	var sum T
+	if len(weights) != r.Len() {
+		return sum, false
+	}
+	k := 0
+	r.ForEach(func(v T) bool {
+		sum += v * weights[k]
+		k++
+		return true
+	})
+	return sum, true
+}
+
+// CumSum returns the running totals of the elements,
+// the k-th result is the sum of the elements from index 0 to k
+func CumSum[T Number](r *RingBuffer[T]) []T {
+	res := make([]T, 0, r.Len())
+	var sum T
+	r.ForEach(func(v T) bool {
+		sum += v
+		res = append(res, sum)
+		return true
+	})
+	return res
+}
+
+// AutoCorr returns the sample autocorrelation of the elements at the given lag
+// ok will be false if lag is out of [0, Len()), or all elements are equal
+func AutoCorr[T Number](r *RingBuffer[T], lag int) (float64, bool) {
+	n := r.Len()
+	if lag < 0 || lag >= n {
+		return 0, false
+	}
+	values := make([]float64, 0, n)
+	var mean float64
+	r.ForEach(func(v T) bool {
+		values = append(values, float64(v))
+		mean += float64(v)
+		return true
+	})
+	mean /= float64(n)
+	var variance, cov float64
+	for k, v := range values {
+		d := v - mean
+		variance += d * d
+		if k+lag < n {
+			cov += d * (values[k+lag] - mean)
+		}
+	}
+	if variance == 0 {
+		return 0, false
+	}
+	return cov / variance, true
+}
+
+// Range returns the difference between the largest and the smallest elements
+// ok will be false if the buffer is empty
+func Range[T Number](r *RingBuffer[T]) (T, bool) {
+	lo, hi, ok := minMax(r)
+	return hi - lo, ok
+}
+
+// EWSum returns the exponentially weighted sum of the elements
+// The last pushed element has weight 1, and each earlier element's weight is multiplied by decay
+// It will panic if decay is not in (0, 1)
+func EWSum[T Number](r *RingBuffer[T], decay float64) float64 {
+	if decay <= 0 || decay >= 1 {
+		panic("decay must be in (0, 1)")
+	}
+	var sum float64
+	w := 1.0
+	r.ForEachReversed(func(v T) bool {
+		sum += float64(v) * w
+		w *= decay
+		return true
+	})
+	return sum
+}
+
+// SignChanges returns how many times the sign changes between consecutive elements
+// Zero elements are skipped, so the sign is compared with the last non-zero element
+func SignChanges[T Number](r *RingBuffer[T]) int {
+	var zero T
+	changes := 0
+	prev := 0
+	r.ForEach(func(v T) bool {
+		sign := 0
+		if v > zero {
+			sign = 1
+		} else if v < zero {
+			sign = -1
+		}
+		if sign != 0 {
+			if prev != 0 && sign != prev {
+				changes++
+			}
+			prev = sign
+		}
+		return true
+	})
+	return changes
+}
+
+// Bucketize counts the elements in each of the equal width buckets over [lo, hi)
+// Elements less than lo are counted in the first bucket,
+// and elements not less than hi are counted in the last bucket
+// It will panic if buckets is less than 1 or hi is not greater than lo
+func Bucketize[T Number](r *RingBuffer[T], lo, hi T, buckets int) []int {
+	if buckets < 1 {
+		panic("bucket count must be greater than 0")
+	}
+	if hi <= lo {
+		panic("max must be greater than min")
+	}
+	counts := make([]int, buckets)
+	// computed in float64, since the differences may overflow narrow types
+	width := float64(hi) - float64(lo)
+	r.ForEach(func(v T) bool {
+		k := 0
+		if v >= hi {
+			k = buckets - 1
+		} else if v > lo {
+			k = int((float64(v) - float64(lo)) / width * float64(buckets))
+			k = max(0, min(k, buckets-1))
+		}
+		counts[k]++
+		return true
+	})
+	return counts
+}
+
+// MaxSubarray returns the logical index range [start, end] and the sum of the contiguous elements with the maximum sum
+// ok will be false if the buffer is empty
+func MaxSubarray[T Number](r *RingBuffer[T]) (start, end int, sum T, ok bool) {
+	n := r.Len()
+	if n == 0 {
+		return 0, 0, sum, false
+	}
+	sum = r.Get(0)
+	cur, curStart := sum, 0
+	for k := 1; k < n; k++ {
+		v := r.Get(k)
+		if cur <= 0 {
+			cur, curStart = v, k
+		} else {
+			cur += v
+		}
+		if cur > sum {
+			start, end, sum = curStart, k, cur
+		}
+	}
+	return start, end, sum, true
+}
+
+// MovingSum returns the sums of each window of the given width,
+// the k-th result is the sum of the elements from index k to k+width-1
+// An empty slice is returned if the buffer's length is less than width
+func MovingSum[T Number](r *RingBuffer[T], width int) []T {
+	if width < 1 {
+		panic("window width must be greater than 0")
+	}
+	n := r.Len()
+	if n < width {
+		return []T{}
+	}
+	res := make([]T, n-width+1)
+	var sum T
+	for k := range width {
+		sum += r.Get(k)
+	}
+	res[0] = sum
+	for k := width; k < n; k++ {
+		sum += r.Get(k) - r.Get(k-width)
+		res[k-width+1] = sum
+	}
+	return res
+}
+
+// IsStable reports whether the range of the elements is within tolerance
+// An empty or single element buffer is stable
+// It will panic if tolerance is negative or NaN
+func IsStable(r *RingBuffer[float64], tolerance float64) bool {
+	if !(tolerance >= 0) {
+		panic("tolerance must not be negative or NaN")
+	}
+	spread, _ := Range(r)
+	return spread <= tolerance
+}
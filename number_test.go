@@ -0,0 +1,304 @@
+// Ring buffer
+// Copyright (C) 2025  Kevin Z <zyxkad@gmail.com>
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package ringbuf_test
+
+import (
+	"math"
+	"testing"
+
+	. "github.com/kmcsr/go-ringbuf"
+)
+
+func TestTrimmedMean(t *testing.T) {
+	rb := NewRingBuffer[int](10)
+	if _, ok := TrimmedMean(rb, 0.1); ok {
+		t.Errorf("Expect not ok for empty buffer")
+	}
+	for _, v := range []int{100, 3, 1, 4, 1, 5, 9, 2, 6, -50} {
+		rb.Push(v)
+	}
+	// sorted: -50 1 1 2 3 4 5 6 9 100, trimmed: 1 1 2 3 4 5 6 9
+	if got, ok := TrimmedMean(rb, 0.1); !ok || got != 31.0/8 {
+		t.Errorf("Expect %v for trimmed mean, got %v, %v", 31.0/8, got, ok)
+	}
+	if got, ok := TrimmedMean(rb, 0); !ok || got != 8.1 {
+		t.Errorf("Expect %v for untrimmed mean, got %v, %v", 8.1, got, ok)
+	}
+
+	rb2 := NewRingBuffer[float64](2)
+	rb2.Push(1)
+	rb2.Push(2)
+	if got, ok := TrimmedMean(rb2, 0.49); !ok || got != 1.5 {
+		t.Errorf("Expect %v for trimmed mean, got %v, %v", 1.5, got, ok)
+	}
+}
+
+func TestDeltaEncode(t *testing.T) {
+	rb := NewRingBuffer[uint8](4)
+	for _, v := range []uint8{1, 10, 12, 11, 200, 3} {
+		rb.Push(v)
+	}
+	// buffer: 12 11 200 3
+	encoded := DeltaEncode(rb)
+	for i, v := range []uint8{12, 255, 189, 59} {
+		if encoded[i] != v {
+			t.Errorf("Expect %d at i %d of encoded, got %d", v, i, encoded[i])
+		}
+	}
+	decoded := DeltaDecode(encoded)
+	if len(decoded) != rb.Len() {
+		t.Fatalf("Expect %d for decoded length, got %d", rb.Len(), len(decoded))
+	}
+	for i, v := range decoded {
+		if want := rb.Get(i); v != want {
+			t.Errorf("Expect %d at i %d of decoded, got %d", want, i, v)
+		}
+	}
+	if got := DeltaEncode(NewRingBuffer[int](1)); len(got) != 0 {
+		t.Errorf("Expect empty result for empty buffer, got %v", got)
+	}
+}
+
+func TestWeightedSum(t *testing.T) {
+	rb := NewRingBuffer[int](3)
+	for _, v := range []int{9, 2, 3, 4} {
+		rb.Push(v)
+	}
+	if got, ok := WeightedSum(rb, []int{1, 2, 3}); !ok || got != 2*1+3*2+4*3 {
+		t.Errorf("Expect %d for weighted sum, got %d, %v", 2*1+3*2+4*3, got, ok)
+	}
+	if _, ok := WeightedSum(rb, []int{1, 2}); ok {
+		t.Errorf("Expect not ok for mismatched weights")
+	}
+	if got, ok := WeightedSum(NewRingBuffer[int](2), nil); !ok || got != 0 {
+		t.Errorf("Expect 0 for weighted sum of empty buffer, got %d, %v", got, ok)
+	}
+}
+
+func TestCumSum(t *testing.T) {
+	rb := NewRingBuffer[int](4)
+	if got := CumSum(rb); len(got) != 0 {
+		t.Errorf("Expect empty result for empty buffer, got %v", got)
+	}
+	for _, v := range []int{100, 1, 2, 3, -4} {
+		rb.Push(v)
+	}
+	got := CumSum(rb)
+	want := []int{1, 3, 6, 2}
+	if len(got) != len(want) {
+		t.Fatalf("Expect %v, got %v", want, got)
+	}
+	for i, v := range want {
+		if got[i] != v {
+			t.Errorf("Expect %d at i %d, got %d", v, i, got[i])
+		}
+	}
+	sum := 0
+	for v := range rb.Iter() {
+		sum += v
+	}
+	if last := got[len(got)-1]; last != sum {
+		t.Errorf("Expect %d for the last element, got %d", sum, last)
+	}
+}
+
+func TestAutoCorr(t *testing.T) {
+	rb := NewRingBuffer[int](4)
+	if _, ok := AutoCorr(rb, 0); ok {
+		t.Errorf("Expect not ok for empty buffer")
+	}
+	for _, v := range []int{5, 1, 2, 1, 2} {
+		rb.Push(v)
+	}
+	// buffer: 1 2 1 2
+	for lag, want := range []float64{1, -0.75, 0.5, -0.25} {
+		if got, ok := AutoCorr(rb, lag); !ok || got != want {
+			t.Errorf("Expect %v for lag %d, got %v, %v", want, lag, got, ok)
+		}
+	}
+	if _, ok := AutoCorr(rb, 4); ok {
+		t.Errorf("Expect not ok for lag equals to length")
+	}
+	if _, ok := AutoCorr(rb, -1); ok {
+		t.Errorf("Expect not ok for negative lag")
+	}
+}
+
+func TestRange(t *testing.T) {
+	rb := NewRingBuffer[float64](4)
+	if _, ok := Range(rb); ok {
+		t.Errorf("Expect not ok for empty buffer")
+	}
+	rb.Push(3.5)
+	if got, ok := Range(rb); !ok || got != 0 {
+		t.Errorf("Expect 0 for single element range, got %v, %v", got, ok)
+	}
+	for _, v := range []float64{2, -1, 6, 1, 0.5} {
+		rb.Push(v)
+	}
+	// buffer: -1 6 1 0.5
+	if got, ok := Range(rb); !ok || got != 7 {
+		t.Errorf("Expect 7 for range, got %v, %v", got, ok)
+	}
+}
+
+func TestEWSum(t *testing.T) {
+	rb := NewRingBuffer[int](3)
+	if got := EWSum(rb, 0.5); got != 0 {
+		t.Errorf("Expect 0 for empty buffer, got %v", got)
+	}
+	for _, v := range []int{100, 8, 4, 2} {
+		rb.Push(v)
+	}
+	// 2*1 + 4*0.5 + 8*0.25
+	if got := EWSum(rb, 0.5); got != 6 {
+		t.Errorf("Expect 6 for weighted sum, got %v", got)
+	}
+}
+
+func TestSignChanges(t *testing.T) {
+	expect := func(input []float64, want int) {
+		rb := NewRingBuffer[float64](len(input) + 1)
+		for _, v := range input {
+			rb.Push(v)
+		}
+		if got := SignChanges(rb); got != want {
+			t.Errorf("Expect %d sign changes for %v, got %d", want, input, got)
+		}
+	}
+	expect([]float64{}, 0)
+	expect([]float64{1, -1, 1, -1, 1}, 4)
+	expect([]float64{-3, -2, -1, 0, 1, 2, 3}, 1)
+	expect([]float64{1, 2, 3, 4}, 0)
+	expect([]float64{1, 0, 1, 0, -1}, 1)
+	expect([]float64{0, 0, 0}, 0)
+}
+
+func TestBucketize(t *testing.T) {
+	rb := NewRingBuffer[int](10)
+	for _, v := range []int{-5, 0, 4, 5, 9, 10, 14, 15, 19, 20} {
+		rb.Push(v)
+	}
+	got := Bucketize(rb, 0, 20, 4)
+	want := []int{3, 2, 2, 3}
+	for i, v := range want {
+		if got[i] != v {
+			t.Errorf("Expect %v for buckets, got %v", want, got)
+			break
+		}
+	}
+	if got := Bucketize(rb, 0, 20, 1); got[0] != 10 {
+		t.Errorf("Expect all elements in a single bucket, got %v", got)
+	}
+
+	narrow := NewRingBuffer[int8](8)
+	for _, v := range []int8{-128, -65, -64, 0, 63, 64, 126, 127} {
+		narrow.Push(v)
+	}
+	for _, c := range []struct {
+		lo, hi int8
+		want   []int
+	}{
+		{-128, 127, []int{2, 1, 2, 3}},
+		{-100, 100, []int{3, 0, 1, 4}},
+	} {
+		got := Bucketize(narrow, c.lo, c.hi, 4)
+		for i, v := range c.want {
+			if got[i] != v {
+				t.Errorf("Expect %v for buckets over [%d, %d), got %v", c.want, c.lo, c.hi, got)
+				break
+			}
+		}
+	}
+}
+
+func TestMaxSubarray(t *testing.T) {
+	rb := NewRingBuffer[int](9)
+	if _, _, _, ok := MaxSubarray(rb); ok {
+		t.Errorf("Expect not ok for empty buffer")
+	}
+	for _, v := range []int{7, 7, -2, 1, -3, 4, -1, 2, 1, -5, 4} {
+		rb.Push(v)
+	}
+	// buffer: -2 1 -3 4 -1 2 1 -5 4
+	if start, end, sum, ok := MaxSubarray(rb); !ok || start != 3 || end != 6 || sum != 6 {
+		t.Errorf("Expect [3, 6] with sum 6, got [%d, %d] with sum %d, %v", start, end, sum, ok)
+	}
+	neg := NewRingBuffer[int](3)
+	for _, v := range []int{-3, -1, -2} {
+		neg.Push(v)
+	}
+	if start, end, sum, ok := MaxSubarray(neg); !ok || start != 1 || end != 1 || sum != -1 {
+		t.Errorf("Expect [1, 1] with sum -1, got [%d, %d] with sum %d, %v", start, end, sum, ok)
+	}
+}
+
+func TestMovingSum(t *testing.T) {
+	rb := NewRingBuffer[int](6)
+	for _, v := range []int{9, 9, 3, -1, 4, 1, 5, 9} {
+		rb.Push(v)
+	}
+	for width := 1; width <= 7; width++ {
+		got := MovingSum(rb, width)
+		if want := max(rb.Len()-width+1, 0); len(got) != want {
+			t.Errorf("Expect %d sums with width %d, got %v", want, width, got)
+			continue
+		}
+		for k := range got {
+			sum := 0
+			for i := k; i < k+width; i++ {
+				sum += rb.Get(i)
+			}
+			if got[k] != sum {
+				t.Errorf("Expect %d at i %d with width %d, got %d", sum, k, width, got[k])
+			}
+		}
+	}
+}
+
+func TestIsStable(t *testing.T) {
+	rb := NewRingBuffer[float64](4)
+	if !IsStable(rb, 0) {
+		t.Errorf("Expect empty buffer is stable")
+	}
+	rb.Push(10)
+	if !IsStable(rb, 0) {
+		t.Errorf("Expect single element buffer is stable")
+	}
+	for _, v := range []float64{10.01, 9.99, 10.02} {
+		rb.Push(v)
+	}
+	if !IsStable(rb, 0.05) {
+		t.Errorf("Expect tight cluster is stable")
+	}
+	rb.Push(12)
+	if IsStable(rb, 0.05) {
+		t.Errorf("Expect spread out window is not stable")
+	}
+	if !IsStable(rb, 3) {
+		t.Errorf("Expect stable with a larger tolerance")
+	}
+	for _, tolerance := range []float64{-1, math.NaN()} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expect panic for tolerance %v", tolerance)
+				}
+			}()
+			IsStable(NewRingBuffer[float64](4), tolerance)
+		}()
+	}
+}
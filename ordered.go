@@ -0,0 +1,313 @@
+// Ring buffer
+// Copyright (C) 2025  Kevin Z <zyxkad@gmail.com>
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package ringbuf
+
+import (
+	"cmp"
+	"slices"
+	"sort"
+)
+
+// InsertAllSorted inserts all values into a buffer which is sorted in ascending order, and keeps it sorted
+// If there is not enough space, the smallest elements will be evicted
+func InsertAllSorted[T cmp.Ordered](r *RingBuffer[T], vs []T) {
+	if len(vs) == 0 {
+		return
+	}
+	sorted := slices.Clone(vs)
+	slices.Sort(sorted)
+	merged := make([]T, 0, r.Len()+len(sorted))
+	k := 0
+	r.ForEach(func(v T) bool {
+		for k < len(sorted) && sorted[k] < v {
+			merged = append(merged, sorted[k])
+			k++
+		}
+		merged = append(merged, v)
+		return true
+	})
+	merged = append(merged, sorted[k:]...)
+	if n := len(merged) - r.Cap(); n > 0 {
+		merged = merged[n:]
+		r.overflowed = true
+	}
+	r.replace(merged)
+}
+
+// quickselect reorders s so that s[k] is the k-th smallest element,
+// with all elements before k not greater than it and all elements after k not less than it
+// It uses a three-way partition, so duplicated elements do not degrade it to quadratic time
+func quickselect[T cmp.Ordered](s []T, k int) {
+	lo, hi := 0, len(s)-1
+	for lo < hi {
+		pivot := s[lo+(hi-lo)/2]
+		// s[lo:lt] < pivot, s[lt:i] == pivot, s[gt:hi+1] > pivot
+		lt, i, gt := lo, lo, hi+1
+		for i < gt {
+			switch {
+			case s[i] < pivot:
+				s[i], s[lt] = s[lt], s[i]
+				lt++
+				i++
+			case s[i] > pivot:
+				gt--
+				s[i], s[gt] = s[gt], s[i]
+			default:
+				i++
+			}
+		}
+		switch {
+		case k < lt:
+			hi = lt - 1
+		case k >= gt:
+			lo = gt
+		default:
+			return
+		}
+	}
+}
+
+// Median returns the median element of the buffer
+// The lower one of the two middle elements is returned when the length is even
+// ok will be false if the buffer is empty
+func Median[T cmp.Ordered](r *RingBuffer[T]) (v T, ok bool) {
+	n := r.Len()
+	if n == 0 {
+		return v, false
+	}
+	values := r.ToSlice()
+	k := (n - 1) / 2
+	quickselect(values, k)
+	return values[k], true
+}
+
+// WeightedMedian returns the element at which the cumulative weight, in ascending order of values, reaches half of the total weight
+// weights[k] is the weight of the element at logical index k
+// ok will be false if the buffer is empty, the lengths mismatch, or the total weight is not positive
+func WeightedMedian[T cmp.Ordered](r *RingBuffer[T], weights []float64) (v T, ok bool) {
+	n := r.Len()
+	if n == 0 || n != len(weights) {
+		return v, false
+	}
+	type pair struct {
+		value  T
+		weight float64
+	}
+	pairs := make([]pair, 0, n)
+	total := 0.0
+	r.ForEach(func(v T) bool {
+		w := weights[len(pairs)]
+		pairs = append(pairs, pair{v, w})
+		total += w
+		return true
+	})
+	if !(total > 0) {
+		return v, false
+	}
+	slices.SortFunc(pairs, func(a, b pair) int {
+		return cmp.Compare(a.value, b.value)
+	})
+	half := total / 2
+	sum := 0.0
+	for _, p := range pairs {
+		sum += p.weight
+		if sum >= half {
+			return p.value, true
+		}
+	}
+	return pairs[n-1].value, true
+}
+
+// PushMonotonic pushes v only if the buffer is empty or v is greater than the last pushed element
+// It returns whether v is pushed
+func PushMonotonic[T cmp.Ordered](r *RingBuffer[T], v T) bool {
+	if last, ok := r.PeekBack(); ok && v <= last {
+		return false
+	}
+	r.Push(v)
+	return true
+}
+
+// minMax returns the smallest and the largest elements
+// ok will be false if the buffer is empty
+func minMax[T cmp.Ordered](r *RingBuffer[T]) (lo, hi T, ok bool) {
+	r.ForEach(func(v T) bool {
+		if !ok {
+			lo, hi, ok = v, v, true
+		} else if v < lo {
+			lo = v
+		} else if v > hi {
+			hi = v
+		}
+		return true
+	})
+	return
+}
+
+// TopK returns the k largest elements in descending order
+// All elements are returned if the buffer's length is less than k
+// The buffer will not be modified
+func TopK[T cmp.Ordered](r *RingBuffer[T], k int) []T {
+	n := r.Len()
+	if k <= 0 || n == 0 {
+		return []T{}
+	}
+	values := r.ToSlice()
+	if k < n {
+		quickselect(values, n-k)
+		values = values[n-k:]
+	}
+	slices.SortFunc(values, func(a, b T) int {
+		return cmp.Compare(b, a)
+	})
+	return values
+}
+
+// TrimByValue removes the leading elements which are less than limit
+// It stops at the first element that is not less than limit
+func TrimByValue[T cmp.Ordered](r *RingBuffer[T], limit T) {
+	for r.hasElem && r.buf[r.i] < limit {
+		r.Poll()
+	}
+}
+
+// LowerBound returns the logical index of the first element not less than target, or Len() if there is none
+// The buffer must be sorted in ascending order
+func LowerBound[T cmp.Ordered](r *RingBuffer[T], target T) int {
+	return sort.Search(r.Len(), func(k int) bool {
+		return r.Get(k) >= target
+	})
+}
+
+// UpperBound returns the logical index of the first element greater than target, or Len() if there is none
+// The buffer must be sorted in ascending order
+func UpperBound[T cmp.Ordered](r *RingBuffer[T], target T) int {
+	return sort.Search(r.Len(), func(k int) bool {
+		return r.Get(k) > target
+	})
+}
+
+// PercentAbove returns the fraction in [0, 1] of elements which are greater than threshold
+// ok will be false if the buffer is empty
+func PercentAbove[T cmp.Ordered](r *RingBuffer[T], threshold T) (float64, bool) {
+	n := r.Len()
+	if n == 0 {
+		return 0, false
+	}
+	count := 0
+	r.ForEach(func(v T) bool {
+		if v > threshold {
+			count++
+		}
+		return true
+	})
+	return float64(count) / float64(n), true
+}
+
+// Sorted returns a newly allocated slice of the elements sorted in ascending order
+// The buffer will not be modified
+func Sorted[T cmp.Ordered](r *RingBuffer[T]) []T {
+	values := r.ToSlice()
+	slices.Sort(values)
+	return values
+}
+
+// FrequencyByValue returns the distinct values in the buffer with their counts,
+// sorted by value in ascending order
+func FrequencyByValue[T cmp.Ordered](r *RingBuffer[T]) []struct {
+	Value T
+	Count int
+} {
+	counts := make(map[T]int)
+	r.ForEach(func(v T) bool {
+		counts[v]++
+		return true
+	})
+	res := make([]struct {
+		Value T
+		Count int
+	}, 0, len(counts))
+	for v, c := range counts {
+		res = append(res, struct {
+			Value T
+			Count int
+		}{Value: v, Count: c})
+	}
+	slices.SortFunc(res, func(a, b struct {
+		Value T
+		Count int
+	}) int {
+		return cmp.Compare(a.Value, b.Value)
+	})
+	return res
+}
+
+// CanonicalCyclic rotates the elements so the buffer starts with the rotation
+// which is lexicographically smallest among all rotations
+// Buffers which are rotations of each other have identical elements after this call
+func CanonicalCyclic[T cmp.Ordered](r *RingBuffer[T]) {
+	values := r.ToSlice()
+	n := len(values)
+	a, b, k := 0, 1, 0
+	for a < n && b < n && k < n {
+		x, y := values[(a+k)%n], values[(b+k)%n]
+		if x == y {
+			k++
+			continue
+		}
+		if x > y {
+			a += k + 1
+		} else {
+			b += k + 1
+		}
+		if a == b {
+			b++
+		}
+		k = 0
+	}
+	r.Rotate(min(a, b))
+}
+
+// mergeCount sorts s in ascending order using tmp as scratch space, and returns the number of inversions in s
+// tmp must have the same length as s
+func mergeCount[T cmp.Ordered](s, tmp []T) int {
+	n := len(s)
+	if n < 2 {
+		return 0
+	}
+	mid := n / 2
+	count := mergeCount(s[:mid], tmp[:mid]) + mergeCount(s[mid:], tmp[mid:])
+	copy(tmp, s)
+	a, b := 0, mid
+	for k := range n {
+		if b >= n || (a < mid && tmp[a] <= tmp[b]) {
+			s[k] = tmp[a]
+			a++
+		} else {
+			s[k] = tmp[b]
+			count += mid - a
+			b++
+		}
+	}
+	return count
+}
+
+// Inversions returns the number of logical index pairs (i, j) where i < j and Get(i) > Get(j)
+// The buffer will not be modified
+func Inversions[T cmp.Ordered](r *RingBuffer[T]) int {
+	values := r.ToSlice()
+	return mergeCount(values, make([]T, len(values)))
+}
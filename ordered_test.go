@@ -0,0 +1,428 @@
+// Ring buffer
+// Copyright (C) 2025  Kevin Z <zyxkad@gmail.com>
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package ringbuf_test
+
+import (
+	"slices"
+	"testing"
+
+	. "github.com/kmcsr/go-ringbuf"
+)
+
+func TestInsertAllSorted(t *testing.T) {
+	rb := NewRingBuffer[int](6)
+	for _, v := range []int{0, 0, 2, 5, 8} {
+		rb.Push(v)
+	}
+	rb.Poll()
+	rb.Poll()
+	// buffer: 2 5 8
+	InsertAllSorted(rb, []int{7, 1, 3})
+	expect := func(want []int) {
+		if got := rb.Len(); got != len(want) {
+			t.Errorf("Expect %d for length, got %d", len(want), got)
+			return
+		}
+		for i, v := range want {
+			if got := rb.Get(i); got != v {
+				t.Errorf("Expect %d at i %d, got %d", v, i, got)
+			}
+		}
+	}
+	expect([]int{1, 2, 3, 5, 7, 8})
+	if rb.HasOverflowed() {
+		t.Errorf("Expect not overflowed")
+	}
+	in := []int{9, 0, 6, 4}
+	InsertAllSorted(rb, in)
+	expect([]int{4, 5, 6, 7, 8, 9})
+	if !rb.HasOverflowed() {
+		t.Errorf("Expect overflowed")
+	}
+	if in[0] != 9 || in[1] != 0 {
+		t.Errorf("Expect input slice unmodified, got %v", in)
+	}
+	rb.Push(10)
+	expect([]int{5, 6, 7, 8, 9, 10})
+}
+
+func TestMedian(t *testing.T) {
+	rb := NewRingBuffer[int](5)
+	if _, ok := Median(rb); ok {
+		t.Errorf("Expect not ok for empty buffer")
+	}
+	expect := func(want int) {
+		if got, ok := Median(rb); !ok || got != want {
+			t.Errorf("Expect %d for median, got %d, %v", want, got, ok)
+		}
+	}
+	rb.Push(7)
+	expect(7)
+	rb.Push(3)
+	expect(3)
+	rb.Push(5)
+	expect(5)
+	rb.Push(1)
+	// 1 3 5 7
+	expect(3)
+	rb.Push(9)
+	rb.Push(4)
+	// buffer: 3 5 1 9 4
+	expect(4)
+	rb.Push(4)
+	rb.Push(4)
+	// buffer: 1 9 4 4 4
+	expect(4)
+	for i, v := range []int{1, 9, 4, 4, 4} {
+		if got := rb.Get(i); got != v {
+			t.Errorf("Expect %d at i %d, got %d", v, i, got)
+		}
+	}
+}
+
+func TestWeightedMedian(t *testing.T) {
+	rb := NewRingBuffer[int](5)
+	if _, ok := WeightedMedian(rb, nil); ok {
+		t.Errorf("Expect not ok for empty buffer")
+	}
+	for _, v := range []int{0, 0, 3, 5, 1, 9, 4} {
+		rb.Push(v)
+	}
+	// buffer: 3 5 1 9 4
+	if _, ok := WeightedMedian(rb, []float64{1, 1, 1}); ok {
+		t.Errorf("Expect not ok for mismatched weights")
+	}
+	if _, ok := WeightedMedian(rb, []float64{0, 0, 0, 0, 0}); ok {
+		t.Errorf("Expect not ok for zero total weight")
+	}
+	for _, c := range []struct {
+		weights []float64
+		want    int
+	}{
+		{[]float64{1, 1, 1, 1, 1}, 4},
+		{[]float64{1, 1, 1, 1, 4}, 4},
+		{[]float64{4, 1, 1, 1, 1}, 3},
+		{[]float64{1, 1, 1, 6, 1}, 9},
+		{[]float64{0, 0, 1, 0, 0}, 1},
+	} {
+		if got, ok := WeightedMedian(rb, c.weights); !ok || got != c.want {
+			t.Errorf("Expect %d for weights %v, got %d, %v", c.want, c.weights, got, ok)
+		}
+	}
+}
+
+func TestMedianDuplicates(t *testing.T) {
+	rb := NewRingBuffer[int](100000)
+	for range rb.Cap() {
+		rb.Push(7)
+	}
+	if got, ok := Median(rb); !ok || got != 7 {
+		t.Errorf("Expect 7 for median of constant window, got %d, %v", got, ok)
+	}
+	for i := range rb.Cap() {
+		rb.Push(i % 3 / 2)
+	}
+	// two thirds zeros and one third ones
+	if got, ok := Median(rb); !ok || got != 0 {
+		t.Errorf("Expect 0 for median of near constant window, got %d, %v", got, ok)
+	}
+	small := NewRingBuffer[int](101)
+	for i := range small.Cap() {
+		small.Push(i * 7919 % 5)
+	}
+	want := Sorted(small)[50]
+	if got, ok := Median(small); !ok || got != want {
+		t.Errorf("Expect %d for median, got %d, %v", want, got, ok)
+	}
+}
+
+func BenchmarkMedianConstant(b *testing.B) {
+	rb := NewRingBuffer[int](100000)
+	for range rb.Cap() {
+		rb.Push(7)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		Median(rb)
+	}
+}
+
+func TestPushMonotonic(t *testing.T) {
+	rb := NewRingBuffer[int](3)
+	for _, c := range []struct {
+		v    int
+		want bool
+	}{{5, true}, {6, true}, {6, false}, {4, false}, {8, true}, {9, true}, {7, false}, {10, true}} {
+		if got := PushMonotonic(rb, c.v); got != c.want {
+			t.Errorf("Expect %v when push %d, got %v", c.want, c.v, got)
+		}
+	}
+	for i, v := range []int{8, 9, 10} {
+		if got := rb.Get(i); got != v {
+			t.Errorf("Expect %d at i %d, got %d", v, i, got)
+		}
+	}
+}
+
+func TestTopK(t *testing.T) {
+	rb := NewRingBuffer[int](6)
+	for _, v := range []int{99, 3, 7, 1, 7, 5, 2} {
+		rb.Push(v)
+	}
+	// buffer: 3 7 1 7 5 2
+	expect := func(k int, want []int) {
+		got := TopK(rb, k)
+		if len(got) != len(want) {
+			t.Errorf("Expect %v for top %d, got %v", want, k, got)
+			return
+		}
+		for i, v := range want {
+			if got[i] != v {
+				t.Errorf("Expect %v for top %d, got %v", want, k, got)
+				return
+			}
+		}
+	}
+	expect(0, []int{})
+	expect(1, []int{7})
+	expect(2, []int{7, 7})
+	expect(3, []int{7, 7, 5})
+	expect(10, []int{7, 7, 5, 3, 2, 1})
+	for i, v := range []int{3, 7, 1, 7, 5, 2} {
+		if got := rb.Get(i); got != v {
+			t.Errorf("Expect %d at i %d, got %d", v, i, got)
+		}
+	}
+}
+
+func TestTopKDuplicates(t *testing.T) {
+	rb := NewRingBuffer[int](100000)
+	for range rb.Cap() {
+		rb.Push(7)
+	}
+	got := TopK(rb, 10)
+	if len(got) != 10 {
+		t.Fatalf("Expect 10 elements for top 10, got %d", len(got))
+	}
+	for i, v := range got {
+		if v != 7 {
+			t.Errorf("Expect 7 at i %d of top 10, got %d", i, v)
+		}
+	}
+	for i := range rb.Cap() {
+		rb.Push(i % 4 / 3 * (i % 5))
+	}
+	// mostly zeros, with a few 1 to 4
+	want := Sorted(rb)
+	slices.Reverse(want)
+	got = TopK(rb, 1000)
+	if !slices.Equal(got, want[:1000]) {
+		t.Errorf("Expect top 1000 to match the sorted elements")
+	}
+}
+
+func TestTrimByValue(t *testing.T) {
+	rb := NewRingBuffer[int](5)
+	for _, v := range []int{0, 1, 2, 3, 5, 4, 6} {
+		rb.Push(v)
+	}
+	// buffer: 2 3 5 4 6
+	TrimByValue(rb, 5)
+	for i, v := range []int{5, 4, 6} {
+		if got := rb.Get(i); got != v {
+			t.Errorf("Expect %d at i %d, got %d", v, i, got)
+		}
+	}
+	if got := rb.Len(); got != 3 {
+		t.Errorf("Expect 3 for length, got %d", got)
+	}
+	TrimByValue(rb, 0)
+	if got := rb.Len(); got != 3 {
+		t.Errorf("Expect 3 for length, got %d", got)
+	}
+	TrimByValue(rb, 10)
+	if got := rb.Len(); got != 0 {
+		t.Errorf("Expect 0 for length, got %d", got)
+	}
+}
+
+func TestBounds(t *testing.T) {
+	rb := NewRingBuffer[int](7)
+	for _, v := range []int{0, 0, 1, 3, 3, 3, 5, 8, 9} {
+		rb.Push(v)
+	}
+	// buffer: 1 3 3 3 5 8 9
+	for _, c := range []struct{ target, lower, upper int }{
+		{0, 0, 0},
+		{1, 0, 1},
+		{2, 1, 1},
+		{3, 1, 4},
+		{8, 5, 6},
+		{9, 6, 7},
+		{10, 7, 7},
+	} {
+		if got := LowerBound(rb, c.target); got != c.lower {
+			t.Errorf("Expect %d for lower bound of %d, got %d", c.lower, c.target, got)
+		}
+		if got := UpperBound(rb, c.target); got != c.upper {
+			t.Errorf("Expect %d for upper bound of %d, got %d", c.upper, c.target, got)
+		}
+	}
+	empty := NewRingBuffer[int](1)
+	if got := LowerBound(empty, 1); got != 0 {
+		t.Errorf("Expect 0 for lower bound of empty buffer, got %d", got)
+	}
+	if got := UpperBound(empty, 1); got != 0 {
+		t.Errorf("Expect 0 for upper bound of empty buffer, got %d", got)
+	}
+}
+
+func TestPercentAbove(t *testing.T) {
+	rb := NewRingBuffer[int](4)
+	if _, ok := PercentAbove(rb, 0); ok {
+		t.Errorf("Expect not ok for empty buffer")
+	}
+	for _, v := range []int{100, 10, 20, 30, 40} {
+		rb.Push(v)
+	}
+	for _, c := range []struct {
+		threshold int
+		want      float64
+	}{{5, 1}, {10, 0.75}, {25, 0.5}, {39, 0.25}, {40, 0}, {50, 0}} {
+		if got, ok := PercentAbove(rb, c.threshold); !ok || got != c.want {
+			t.Errorf("Expect %v for threshold %d, got %v, %v", c.want, c.threshold, got, ok)
+		}
+	}
+}
+
+func TestSorted(t *testing.T) {
+	rb := NewRingBuffer[int](5)
+	for _, v := range []int{0, 0, 4, 1, 3, 1, 2} {
+		rb.Push(v)
+	}
+	got := Sorted(rb)
+	for i, v := range []int{1, 1, 2, 3, 4} {
+		if got[i] != v {
+			t.Errorf("Expect %d at i %d of sorted, got %d", v, i, got[i])
+		}
+	}
+	for i, v := range []int{4, 1, 3, 1, 2} {
+		if got := rb.Get(i); got != v {
+			t.Errorf("Expect %d at i %d, got %d", v, i, got)
+		}
+	}
+	if got := Sorted(NewRingBuffer[int](1)); len(got) != 0 {
+		t.Errorf("Expect empty result for empty buffer, got %v", got)
+	}
+}
+
+func TestFrequencyByValue(t *testing.T) {
+	rb := NewRingBuffer[int](6)
+	for _, v := range []int{9, 3, 1, 3, 2, 1, 3} {
+		rb.Push(v)
+	}
+	// buffer: 3 1 3 2 1 3
+	res := FrequencyByValue(rb)
+	values := []int{1, 2, 3}
+	counts := []int{2, 1, 3}
+	if len(res) != len(values) {
+		t.Fatalf("Expect %d distinct values, got %v", len(values), res)
+	}
+	for i, e := range res {
+		if e.Value != values[i] || e.Count != counts[i] {
+			t.Errorf("Expect %d:%d at i %d, got %d:%d", values[i], counts[i], i, e.Value, e.Count)
+		}
+	}
+	single := NewRingBuffer[int](1)
+	single.Push(5)
+	if res := FrequencyByValue(single); len(res) != 1 || res[0].Value != 5 || res[0].Count != 1 {
+		t.Errorf("Expect [{5 1}], got %v", res)
+	}
+}
+
+func TestCanonicalCyclic(t *testing.T) {
+	newBuf := func(vs ...int) *RingBuffer[int] {
+		rb := NewRingBuffer[int](6)
+		for _, v := range vs {
+			rb.Push(v)
+		}
+		return rb
+	}
+	expect := func(rb *RingBuffer[int], want []int) {
+		t.Helper()
+		CanonicalCyclic(rb)
+		if got := rb.Len(); got != len(want) {
+			t.Errorf("Expect %d for length, got %d", len(want), got)
+			return
+		}
+		for i, v := range want {
+			if got := rb.Get(i); got != v {
+				t.Errorf("Expect %v, got %v", want, rb.ToSlice())
+				return
+			}
+		}
+	}
+	expect(newBuf(), []int{})
+	expect(newBuf(3), []int{3})
+	expect(newBuf(3, 1, 2), []int{1, 2, 3})
+	expect(newBuf(0, 0, 2, 1, 3, 1, 2, 1), []int{1, 2, 1, 2, 1, 3})
+	expect(newBuf(1, 3, 1, 2, 1, 2), []int{1, 2, 1, 2, 1, 3})
+	expect(newBuf(2, 2, 2), []int{2, 2, 2})
+	expect(newBuf(1, 1, 2, 1, 1, 1), []int{1, 1, 1, 1, 1, 2})
+
+	a := newBuf(9, 9, 4, 2, 7, 2, 5, 2)
+	b := newBuf(2, 5, 2, 4, 2, 7)
+	CanonicalCyclic(a)
+	CanonicalCyclic(b)
+	if ContentHash(a) != ContentHash(b) {
+		t.Errorf("Expect identical canonical forms, got %v and %v", a.ToSlice(), b.ToSlice())
+	}
+}
+
+func TestInversions(t *testing.T) {
+	rb := NewRingBuffer[int](6)
+	if got := Inversions(rb); got != 0 {
+		t.Errorf("Expect 0 inversions for empty buffer, got %d", got)
+	}
+	for i := range 9 {
+		rb.Push(i)
+	}
+	// buffer: 3 4 5 6 7 8, wrapped
+	if got := Inversions(rb); got != 0 {
+		t.Errorf("Expect 0 inversions for sorted buffer, got %d", got)
+	}
+	for i := range 6 {
+		rb.Push(10 - i)
+	}
+	// buffer: 10 9 8 7 6 5
+	if got := Inversions(rb); got != 15 {
+		t.Errorf("Expect 15 inversions for reversed buffer, got %d", got)
+	}
+	for _, v := range []int{3, 1, 4, 1, 5, 9} {
+		rb.Push(v)
+	}
+	// buffer: 3 1 4 1 5 9
+	// (3, 1) (3, 1) (4, 1)
+	if got := Inversions(rb); got != 3 {
+		t.Errorf("Expect 3 inversions, got %d", got)
+	}
+	for i, v := range []int{3, 1, 4, 1, 5, 9} {
+		if got := rb.Get(i); got != v {
+			t.Errorf("Expect %d at i %d, got %d", v, i, got)
+		}
+	}
+}
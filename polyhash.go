@@ -0,0 +1,114 @@
+// Ring buffer
+// Copyright (C) 2025  Kevin Z <zyxkad@gmail.com>
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package ringbuf
+
+import (
+	"math/bits"
+)
+
+const (
+	// PolyHashBase is the base of the polynomial rolling hash
+	PolyHashBase = 257
+	// PolyHashMod is the modulus of the polynomial rolling hash, which is the Mersenne prime 2^61-1
+	PolyHashMod = 1<<61 - 1
+)
+
+func polyMulMod(a, b uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	x := (hi<<3 | lo>>61) + lo&PolyHashMod
+	for x >= PolyHashMod {
+		x -= PolyHashMod
+	}
+	return x
+}
+
+// PolyHashOf returns the polynomial rolling hash of p
+// The hash is sum(p[k] * PolyHashBase^(len(p)-1-k)) mod PolyHashMod
+func PolyHashOf(p []byte) uint64 {
+	var h uint64
+	for _, v := range p {
+		h = (polyMulMod(h, PolyHashBase) + uint64(v)) % PolyHashMod
+	}
+	return h
+}
+
+// PolyHashBuffer is a byte ring buffer which maintains a polynomial rolling hash of its elements
+// The hash is updated incrementally when an element is pushed, polled or overwritten,
+// and it always equals to PolyHashOf the elements from first to last
+type PolyHashBuffer struct {
+	buf  *RingBuffer[byte]
+	pows []uint64
+	hash uint64
+}
+
+func NewPolyHashBuffer(size int) *PolyHashBuffer {
+	buf := NewRingBuffer[byte](size)
+	pows := make([]uint64, size)
+	pows[0] = 1
+	for k := 1; k < size; k++ {
+		pows[k] = polyMulMod(pows[k-1], PolyHashBase)
+	}
+	return &PolyHashBuffer{
+		buf:  buf,
+		pows: pows,
+	}
+}
+
+// Push puts a byte into the buffer and updates the hash
+// It will overwrite the earliest byte if there is no space avaliable
+func (b *PolyHashBuffer) Push(v byte) {
+	if old, ok := b.buf.NextEvict(); ok {
+		b.hash = (b.hash + PolyHashMod - polyMulMod(uint64(old), b.pows[len(b.pows)-1])) % PolyHashMod
+	}
+	b.hash = (polyMulMod(b.hash, PolyHashBase) + uint64(v)) % PolyHashMod
+	b.buf.Push(v)
+}
+
+// Poll removes the earliest pushed byte from the buffer and updates the hash
+func (b *PolyHashBuffer) Poll() (v byte, ok bool) {
+	n := b.buf.Len()
+	if v, ok = b.buf.Poll(); ok {
+		b.hash = (b.hash + PolyHashMod - polyMulMod(uint64(v), b.pows[n-1])) % PolyHashMod
+	}
+	return
+}
+
+// Get returns the i-th byte in the buffer
+// It will panic if index is out of bounds
+func (b *PolyHashBuffer) Get(index int) byte {
+	return b.buf.Get(index)
+}
+
+// Len returns the used space of the buffer
+func (b *PolyHashBuffer) Len() int {
+	return b.buf.Len()
+}
+
+// Cap returns the total space of the buffer
+func (b *PolyHashBuffer) Cap() int {
+	return b.buf.Cap()
+}
+
+// Reset set the buffer's length to zero and resets the hash
+func (b *PolyHashBuffer) Reset() {
+	b.buf.Reset()
+	b.hash = 0
+}
+
+// PolyHash returns the polynomial rolling hash of the bytes in the buffer, from first to last
+func (b *PolyHashBuffer) PolyHash() uint64 {
+	return b.hash
+}
@@ -0,0 +1,73 @@
+// Ring buffer
+// Copyright (C) 2025  Kevin Z <zyxkad@gmail.com>
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package ringbuf_test
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	. "github.com/kmcsr/go-ringbuf"
+)
+
+func TestPolyHashBuffer(t *testing.T) {
+	b := NewPolyHashBuffer(16)
+	expect := func() {
+		t.Helper()
+		data := make([]byte, b.Len())
+		for i := range data {
+			data[i] = b.Get(i)
+		}
+		if got, want := b.PolyHash(), PolyHashOf(data); got != want {
+			t.Fatalf("Expect %d for hash of %v, got %d", want, data, got)
+		}
+	}
+	expect()
+	rng := rand.New(rand.NewPCG(1, 2))
+	for range 1000 {
+		if rng.IntN(3) == 0 {
+			b.Poll()
+		} else {
+			b.Push(byte(rng.Uint32()))
+		}
+		expect()
+	}
+	for range 20 {
+		b.Push(0xff)
+	}
+	expect()
+	b.Reset()
+	expect()
+	if got := PolyHashOf([]byte{1, 2}); got != PolyHashBase+2 {
+		t.Errorf("Expect %d for hash, got %d", PolyHashBase+2, got)
+	}
+}
+
+func TestPolyHashBufferSearch(t *testing.T) {
+	pattern := []byte("needle")
+	target := PolyHashOf(pattern)
+	b := NewPolyHashBuffer(len(pattern))
+	found := -1
+	for i, v := range []byte("haystack with a needle inside") {
+		b.Push(v)
+		if b.Len() == len(pattern) && b.PolyHash() == target {
+			found = i - len(pattern) + 1
+			break
+		}
+	}
+	if found != 16 {
+		t.Errorf("Expect pattern found at 16, got %d", found)
+	}
+}
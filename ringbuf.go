@@ -18,6 +18,10 @@ package ringbuf
 import (
 	"fmt"
 	"iter"
+	"math/rand/v2"
+	"slices"
+	"sort"
+	"sync"
 )
 
 type RingBuffer[T any] struct {
@@ -25,6 +29,8 @@ type RingBuffer[T any] struct {
 	i       int
 	j       int
 	hasElem bool
+
+	overflowed bool
 }
 
 func NewRingBuffer[T any](size int) *RingBuffer[T] {
@@ -39,12 +45,24 @@ func NewRingBuffer[T any](size int) *RingBuffer[T] {
 	}
 }
 
+// NewRingBufferFromSlice creates a ring buffer with the given size, and pushes the elements of initial into it
+// Only the last size elements are kept if there are more elements than size
+func NewRingBufferFromSlice[T any](size int, initial []T) *RingBuffer[T] {
+	r := NewRingBuffer[T](size)
+	if len(initial) > size {
+		initial = initial[len(initial)-size:]
+	}
+	r.replace(initial)
+	return r
+}
+
 // Push puts an element into the ring buffer
 // It will overwrite the earliest element if there is no space avaliable
 func (r *RingBuffer[T]) Push(v T) {
 	r.buf[r.j] = v
 	if r.hasElem {
 		if r.j == r.i {
+			r.overflowed = true
 			r.i++
 			if r.i == len(r.buf) {
 				r.i = 0
@@ -59,6 +77,48 @@ func (r *RingBuffer[T]) Push(v T) {
 	}
 }
 
+// PushSlice puts all elements of vs into the ring buffer in order, as calling Push on each of them
+// If len(vs) is not less than the buffer's capacity, only the last Cap() elements are kept,
+// and they are moved to the start of the backing slice
+// Otherwise, the end index advances by len(vs), and the start index follows it if elements are overwritten
+func (r *RingBuffer[T]) PushSlice(vs []T) {
+	if len(vs) == 0 {
+		return
+	}
+	size := len(r.buf)
+	overflow := r.Len()+len(vs) > size
+	if overflow {
+		r.overflowed = true
+	}
+	if len(vs) >= size {
+		copy(r.buf, vs[len(vs)-size:])
+		r.i = 0
+		r.j = 0
+		r.hasElem = true
+		return
+	}
+	n := copy(r.buf[r.j:], vs)
+	copy(r.buf, vs[n:])
+	r.j += len(vs)
+	if r.j >= size {
+		r.j -= size
+	}
+	if overflow {
+		r.i = r.j
+	}
+	r.hasElem = true
+}
+
+// PushSafe puts an element into the ring buffer only if there is space avaliable
+// It returns false and leaves the buffer untouched if the buffer is full
+func (r *RingBuffer[T]) PushSafe(v T) bool {
+	if r.Full() {
+		return false
+	}
+	r.Push(v)
+	return true
+}
+
 // Poll removes the earliest pushed element from the ring buffer
 func (r *RingBuffer[T]) Poll() (v T, ok bool) {
 	if !r.hasElem {
@@ -75,9 +135,138 @@ func (r *RingBuffer[T]) Poll() (v T, ok bool) {
 	return v, true
 }
 
-// Get returns the i-th element in the buffer
+// PollN removes the earliest pushed elements and copies them into dst, from first to last
+// It returns the number of elements copied, which is the minimum of len(dst) and Len()
+func (r *RingBuffer[T]) PollN(dst []T) int {
+	a, b := r.runs()
+	n := copy(dst, a)
+	clear(a[:n])
+	m := copy(dst[n:], b)
+	clear(b[:m])
+	n += m
+	if n == 0 {
+		return 0
+	}
+	r.i += n
+	if r.i >= len(r.buf) {
+		r.i -= len(r.buf)
+	}
+	if r.i == r.j {
+		r.hasElem = false
+	}
+	return n
+}
+
+// Discard removes at most n earliest pushed elements without returning them, and dereferences them
+// It returns the number of elements removed
+func (r *RingBuffer[T]) Discard(n int) int {
+	n = min(n, r.Len())
+	if n <= 0 {
+		return 0
+	}
+	var empty T
+	for k := range n {
+		r.buf[r.slot(k)] = empty
+	}
+	r.i = r.slot(n)
+	if r.i == r.j {
+		r.hasElem = false
+	}
+	return n
+}
+
+// Peek returns the earliest pushed element without removing it
+func (r *RingBuffer[T]) Peek() (v T, ok bool) {
+	if !r.hasElem {
+		return v, false
+	}
+	return r.buf[r.i], true
+}
+
+// PeekBack returns the last pushed element without removing it
+func (r *RingBuffer[T]) PeekBack() (v T, ok bool) {
+	if !r.hasElem {
+		return v, false
+	}
+	return r.buf[r.last()], true
+}
+
+// last returns the index of the last pushed element in the backing slice
+func (r *RingBuffer[T]) last() int {
+	if r.j == 0 {
+		return len(r.buf) - 1
+	}
+	return r.j - 1
+}
+
+// ReplaceNewest overwrites the last pushed element with v
+// It returns false if the buffer is empty
+func (r *RingBuffer[T]) ReplaceNewest(v T) bool {
+	if !r.hasElem {
+		return false
+	}
+	r.buf[r.last()] = v
+	return true
+}
+
+// PushFront puts an element before the earliest element of the ring buffer
+// It will overwrite the last pushed element if there is no space avaliable
+func (r *RingBuffer[T]) PushFront(v T) {
+	if r.Full() {
+		r.overflowed = true
+		r.j = r.last()
+	}
+	if r.i == 0 {
+		r.i = len(r.buf)
+	}
+	r.i--
+	r.buf[r.i] = v
+	r.hasElem = true
+}
+
+// PollBack removes the last pushed element from the ring buffer
+func (r *RingBuffer[T]) PollBack() (v T, ok bool) {
+	if !r.hasElem {
+		return v, false
+	}
+	if r.j == 0 {
+		r.j = len(r.buf)
+	}
+	r.j--
+	v, r.buf[r.j] = r.buf[r.j], v
+	if r.i == r.j {
+		r.hasElem = false
+	}
+	return v, true
+}
+
+// runs returns the elements as at most two contiguous parts of the backing slice, from first to last
+func (r *RingBuffer[T]) runs() (a, b []T) {
+	if !r.hasElem {
+		return nil, nil
+	}
+	if r.j > r.i {
+		return r.buf[r.i:r.j], nil
+	}
+	return r.buf[r.i:], r.buf[:r.j]
+}
+
+// replace replaces all elements with vs and moves the first element to the start of the backing slice
+// It assumes len(vs) is not greater than the buffer's capacity
+func (r *RingBuffer[T]) replace(vs []T) {
+	n := copy(r.buf, vs)
+	clear(r.buf[n:])
+	r.i = 0
+	r.j = n
+	if r.j == len(r.buf) {
+		r.j = 0
+	}
+	r.hasElem = n > 0
+}
+
+// checkedSlot translates a logical index to the index of the backing slice
 // It will panic if index is out of bounds
-func (r *RingBuffer[T]) Get(index int) T {
+func (r *RingBuffer[T]) checkedSlot(index int) int {
 	if !r.hasElem {
 		panic(fmt.Errorf("Index %d out of bounds: buffer is empty", index))
 	}
@@ -95,7 +284,19 @@ func (r *RingBuffer[T]) Get(index int) T {
 	} else if i >= r.j {
 		panic(fmt.Errorf("Index %d out of bounds", index))
 	}
-	return r.buf[i]
+	return i
+}
+
+// Get returns the i-th element in the buffer
+// It will panic if index is out of bounds
+func (r *RingBuffer[T]) Get(index int) T {
+	return r.buf[r.checkedSlot(index)]
+}
+
+// Set overwrites the i-th element in the buffer
+// It will panic if index is out of bounds
+func (r *RingBuffer[T]) Set(index int, v T) {
+	r.buf[r.checkedSlot(index)] = v
 }
 
 // Len returns the used space of the buffer
@@ -110,17 +311,48 @@ func (r *RingBuffer[T]) Len() int {
 	return n
 }
 
+// Full reports whether the buffer has no free space
+func (r *RingBuffer[T]) Full() bool {
+	return r.hasElem && r.i == r.j
+}
+
+// IsEmpty reports whether the buffer has no element
+func (r *RingBuffer[T]) IsEmpty() bool {
+	return !r.hasElem
+}
+
 // Cap returns the total space of the buffer
 func (r *RingBuffer[T]) Cap() int {
 	return len(r.buf)
 }
 
+// Resize changes the total space of the buffer, and keeps the order of the elements
+// The earliest elements will be dropped if newSize is less than the buffer's length
+func (r *RingBuffer[T]) Resize(newSize int) {
+	if newSize < 1 {
+		panic("ring buffer's size must be greater than 0")
+	}
+	vs := r.ToSlice()
+	if len(vs) > newSize {
+		vs = vs[len(vs)-newSize:]
+		r.overflowed = true
+	}
+	r.buf = make([]T, newSize)
+	r.replace(vs)
+}
+
+// Size returns both the used space and the total space of the buffer
+func (r *RingBuffer[T]) Size() (length, capacity int) {
+	return r.Len(), len(r.buf)
+}
+
 // Clear set ring buffer's length to zero
 // It does not dereference old elements
 func (r *RingBuffer[T]) Clear() {
 	r.i = 0
 	r.j = 0
 	r.hasElem = false
+	r.overflowed = false
 }
 
 // Reset set ring buffer's length to zero and dereference all elements
@@ -128,12 +360,41 @@ func (r *RingBuffer[T]) Reset() {
 	r.i = 0
 	r.j = 0
 	r.hasElem = false
+	r.overflowed = false
 	var empty T
 	for i := range len(r.buf) {
 		r.buf[i] = empty
 	}
 }
 
+// ClearFunc set ring buffer's length to zero, and calls fn on each element from first to last before dereferencing it
+// Only the elements in the buffer are visited, stale slots are not
+func (r *RingBuffer[T]) ClearFunc(fn func(T)) {
+	a, b := r.runs()
+	for _, run := range [][]T{a, b} {
+		for k, v := range run {
+			fn(v)
+			var empty T
+			run[k] = empty
+		}
+	}
+	r.i = 0
+	r.j = 0
+	r.hasElem = false
+	r.overflowed = false
+}
+
+// HasOverflowed reports whether any element has been overwritten or evicted due to lack of space
+// since the buffer was created, cleared, reset, or ClearOverflowed was called
+func (r *RingBuffer[T]) HasOverflowed() bool {
+	return r.overflowed
+}
+
+// ClearOverflowed resets the flag reported by HasOverflowed
+func (r *RingBuffer[T]) ClearOverflowed() {
+	r.overflowed = false
+}
+
 // ForEach iterate the buffer from first to last
 // if the iterator returns false, the iterate will break
 func (r *RingBuffer[T]) ForEach(iter func(v T) bool) {
@@ -186,6 +447,21 @@ func (r *RingBuffer[T]) ForEachReversed(iter func(v T) bool) {
 	}
 }
 
+// ForEachWithPrev iterate the buffer from first to last with the previous element
+// hasPrev is false only for the first element, and prev will be the zero value then
+// if the iterator returns false, the iterate will break
+func (r *RingBuffer[T]) ForEachWithPrev(iter func(prev, cur T, hasPrev bool) bool) {
+	var prev T
+	hasPrev := false
+	r.ForEach(func(v T) bool {
+		if !iter(prev, v, hasPrev) {
+			return false
+		}
+		prev, hasPrev = v, true
+		return true
+	})
+}
+
 // Iter returns an iterator of the buffer that iterate from first to last
 func (r *RingBuffer[T]) Iter() iter.Seq[T] {
 	return r.ForEach
@@ -195,3 +471,497 @@ func (r *RingBuffer[T]) Iter() iter.Seq[T] {
 func (r *RingBuffer[T]) IterReversed() iter.Seq[T] {
 	return r.ForEachReversed
 }
+
+// Iter2 returns an iterator of the buffer that iterate from first to last with the logical indexes
+func (r *RingBuffer[T]) Iter2() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		k := 0
+		r.ForEach(func(v T) bool {
+			ok := yield(k, v)
+			k++
+			return ok
+		})
+	}
+}
+
+// Iter2Reversed returns an iterator of the buffer that iterate from last to first with the logical indexes
+func (r *RingBuffer[T]) Iter2Reversed() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		k := r.Len() - 1
+		r.ForEachReversed(func(v T) bool {
+			ok := yield(k, v)
+			k--
+			return ok
+		})
+	}
+}
+
+// Drain returns an iterator that removes the elements from first to last as it yields them
+// If the iteration breaks, the element that has been yielded is removed, and the rest stay in the buffer
+func (r *RingBuffer[T]) Drain() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for {
+			v, ok := r.Poll()
+			if !ok || !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// IterN returns an iterator of the buffer that iterate at most n elements from first to last
+func (r *RingBuffer[T]) IterN(n int) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if n <= 0 {
+			return
+		}
+		k := 0
+		r.ForEach(func(v T) bool {
+			k++
+			return yield(v) && k < n
+		})
+	}
+}
+
+// Clone returns a copy of the ring buffer which shares no memory with the original one
+// The elements are copied shallowly, so pointers in them still point to the same values
+func (r *RingBuffer[T]) Clone() *RingBuffer[T] {
+	return &RingBuffer[T]{
+		buf:        slices.Clone(r.buf),
+		i:          r.i,
+		j:          r.j,
+		hasElem:    r.hasElem,
+		overflowed: r.overflowed,
+	}
+}
+
+// Reversed returns a new ring buffer with the same capacity, and the elements in reversed order
+// The original buffer will not be modified
+func (r *RingBuffer[T]) Reversed() *RingBuffer[T] {
+	rb := NewRingBuffer[T](len(r.buf))
+	r.ForEachReversed(func(v T) bool {
+		rb.Push(v)
+		return true
+	})
+	return rb
+}
+
+// Sample returns n elements taken at evenly spaced logical indexes, from first to last
+// The first and the last element are always included when n >= 2
+// If n is not less than the buffer's length, all elements will be returned
+func (r *RingBuffer[T]) Sample(n int) []T {
+	if n < 1 {
+		panic("sample count must be greater than 0")
+	}
+	l := r.Len()
+	if n >= l {
+		return r.ToSlice()
+	}
+	res := make([]T, n)
+	if n == 1 {
+		res[0] = r.Get(0)
+		return res
+	}
+	for k := range n {
+		res[k] = r.Get(k * (l - 1) / (n - 1))
+	}
+	return res
+}
+
+// slot translates a logical index to the index of the backing slice
+// It does not check bounds
+func (r *RingBuffer[T]) slot(index int) int {
+	i := index + r.i
+	if i >= len(r.buf) {
+		i -= len(r.buf)
+	}
+	return i
+}
+
+// swap swaps the elements at logical index a and b
+// It does not check bounds
+func (r *RingBuffer[T]) swap(a, b int) {
+	a, b = r.slot(a), r.slot(b)
+	r.buf[a], r.buf[b] = r.buf[b], r.buf[a]
+}
+
+// reverse reverses the elements in logical range [from, to)
+func (r *RingBuffer[T]) reverse(from, to int) {
+	for to--; from < to; from, to = from+1, to-1 {
+		r.swap(from, to)
+	}
+}
+
+// Rotate shifts the elements cyclically by n positions, modulo the buffer's length
+// A positive n rotates towards the front, so Get(0) will return the element previously at index n
+// A negative n rotates towards the back
+// A full buffer is rotated by only moving its head, without moving any element
+func (r *RingBuffer[T]) Rotate(n int) {
+	l := r.Len()
+	if l == 0 {
+		return
+	}
+	n %= l
+	if n < 0 {
+		n += l
+	}
+	if n == 0 {
+		return
+	}
+	if l == len(r.buf) {
+		r.i = r.slot(n)
+		r.j = r.i
+		return
+	}
+	r.reverse(0, n)
+	r.reverse(n, l)
+	r.reverse(0, l)
+}
+
+// ExtendReversed pushes the elements of other into the ring buffer, from last to first
+// Earlier elements will be overwritten if there is no space avaliable
+func (r *RingBuffer[T]) ExtendReversed(other *RingBuffer[T]) {
+	if other == r {
+		other = r.Clone()
+	}
+	other.ForEachReversed(func(v T) bool {
+		r.Push(v)
+		return true
+	})
+}
+
+// Interleave returns a new ring buffer with the given size,
+// and pushes a[0], b[0], a[1], b[1], ... into it until either a or b is exhausted
+// Earlier elements will be overwritten if the result exceeds the size
+func Interleave[T any](a, b *RingBuffer[T], size int) *RingBuffer[T] {
+	rb := NewRingBuffer[T](size)
+	n := min(a.Len(), b.Len())
+	for k := range n {
+		rb.Push(a.Get(k))
+		rb.Push(b.Get(k))
+	}
+	return rb
+}
+
+// SlidingWindowsIter returns an iterator that yields each overlapping window of the given width, from first to last
+// Each yielded window is a newly allocated slice, so it is safe to be retained
+// Nothing will be yielded if the buffer's length is less than width
+func (r *RingBuffer[T]) SlidingWindowsIter(width int) iter.Seq[[]T] {
+	if width < 1 {
+		panic("window width must be greater than 0")
+	}
+	return func(yield func([]T) bool) {
+		n := r.Len()
+		for s := 0; s+width <= n; s++ {
+			window := make([]T, width)
+			for k := range width {
+				window[k] = r.Get(s + k)
+			}
+			if !yield(window) {
+				return
+			}
+		}
+	}
+}
+
+// PopFrontWhile removes and returns the leading elements that satisfy pred, from first to last
+// It stops at the first element that does not satisfy pred
+func (r *RingBuffer[T]) PopFrontWhile(pred func(T) bool) []T {
+	var res []T
+	for r.hasElem && pred(r.buf[r.i]) {
+		v, _ := r.Poll()
+		res = append(res, v)
+	}
+	return res
+}
+
+// PopBackWhile removes and returns the trailing elements that satisfy pred, from first to last
+// It stops at the first element from the back that does not satisfy pred
+func (r *RingBuffer[T]) PopBackWhile(pred func(T) bool) []T {
+	var res []T
+	for {
+		if v, ok := r.PeekBack(); !ok || !pred(v) {
+			break
+		}
+		v, _ := r.PollBack()
+		res = append(res, v)
+	}
+	slices.Reverse(res)
+	return res
+}
+
+// checkInvariants reports whether the internal indexes are consistent
+func (r *RingBuffer[T]) checkInvariants() bool {
+	if r.i < 0 || r.i >= len(r.buf) || r.j < 0 || r.j >= len(r.buf) {
+		return false
+	}
+	if !r.hasElem && r.i != r.j {
+		return false
+	}
+	return true
+}
+
+// Repair resets the ring buffer to empty if its internal state is inconsistent,
+// and returns whether a repair was made
+// The buffer's capacity is kept, and all elements are dereferenced during a repair
+// A buffer with zero capacity, such as the zero value, cannot be repaired, and false is returned
+func (r *RingBuffer[T]) Repair() bool {
+	if len(r.buf) == 0 || r.checkInvariants() {
+		return false
+	}
+	r.Reset()
+	return true
+}
+
+// NextEvict returns the element that will be overwritten by the next Push
+// ok will be false if the buffer still has free space
+func (r *RingBuffer[T]) NextEvict() (v T, ok bool) {
+	if !r.Full() {
+		return v, false
+	}
+	return r.buf[r.i], true
+}
+
+// truncate keeps the first n elements and dereferences the rest
+func (r *RingBuffer[T]) truncate(n int) {
+	var empty T
+	for k, l := n, r.Len(); k < l; k++ {
+		r.buf[r.slot(k)] = empty
+	}
+	r.j = r.slot(n)
+	r.hasElem = n > 0
+}
+
+// RemoveFunc keeps only the elements which satisfy keep in order, and returns the number of removed elements
+// The freed slots will be dereferenced
+func (r *RingBuffer[T]) RemoveFunc(keep func(T) bool) int {
+	n := r.Len()
+	w := 0
+	for k := range n {
+		v := r.buf[r.slot(k)]
+		if keep(v) {
+			r.buf[r.slot(w)] = v
+			w++
+		}
+	}
+	if w < n {
+		r.truncate(w)
+	}
+	return n - w
+}
+
+// StablePartition moves the elements which satisfy pred before the rest, and keeps the relative order in both groups
+// It returns the logical index of the first element which does not satisfy pred
+// The elements are partitioned in place without allocating, in O(n log n) time
+func (r *RingBuffer[T]) StablePartition(pred func(T) bool) int {
+	n := r.Len()
+	if n == 0 {
+		return 0
+	}
+	return r.stablePartition(0, n, pred)
+}
+
+// stablePartition stable partitions the elements in logical range [from, to) which must not be empty,
+// and returns the logical index of the first element which does not satisfy pred
+func (r *RingBuffer[T]) stablePartition(from, to int, pred func(T) bool) int {
+	if to-from == 1 {
+		if pred(r.buf[r.slot(from)]) {
+			return to
+		}
+		return from
+	}
+	mid := from + (to-from)/2
+	a := r.stablePartition(from, mid, pred)
+	b := r.stablePartition(mid, to, pred)
+	// swap the rejected elements of the left half with the accepted elements of the right half
+	r.reverse(a, mid)
+	r.reverse(mid, b)
+	r.reverse(a, b)
+	return a + b - mid
+}
+
+// Coalesce replaces adjacent elements a and b with merge(a, b) wherever canMerge(a, b) holds
+// Elements are merged from left to right, the merged element is then tested against its next element,
+// and the passes are repeated until no adjacent pair can be merged
+func Coalesce[T any](r *RingBuffer[T], canMerge func(a, b T) bool, merge func(a, b T) T) {
+	for {
+		n := r.Len()
+		if n < 2 {
+			return
+		}
+		w := 0
+		cur := r.buf[r.i]
+		for k := 1; k < n; k++ {
+			v := r.buf[r.slot(k)]
+			if canMerge(cur, v) {
+				cur = merge(cur, v)
+			} else {
+				r.buf[r.slot(w)] = cur
+				w++
+				cur = v
+			}
+		}
+		r.buf[r.slot(w)] = cur
+		w++
+		if w == n {
+			return
+		}
+		r.truncate(w)
+	}
+}
+
+// ToSlice returns a newly allocated slice of the elements, from first to last
+// An empty but non-nil slice is returned if the buffer is empty
+func (r *RingBuffer[T]) ToSlice() []T {
+	a, b := r.runs()
+	res := make([]T, len(a)+len(b))
+	copy(res[copy(res, a):], b)
+	return res
+}
+
+// Last returns a newly allocated slice of at most n last pushed elements, from first to last
+// An empty slice is returned if n <= 0 or the buffer is empty
+func (r *RingBuffer[T]) Last(n int) []T {
+	l := r.Len()
+	n = max(min(n, l), 0)
+	res := make([]T, n)
+	for k := range n {
+		res[k] = r.buf[r.slot(l-n+k)]
+	}
+	return res
+}
+
+// CopyTo copies the earliest pushed elements into dst, from first to last
+// It returns the number of elements copied, which is the minimum of len(dst) and Len()
+// Unlike PollN, CopyTo does not remove the elements from the buffer
+func (r *RingBuffer[T]) CopyTo(dst []T) int {
+	a, b := r.runs()
+	n := copy(dst, a)
+	return n + copy(dst[n:], b)
+}
+
+// CopyToN replaces the content of dst with the elements from first to last
+// It reuses dst's capacity, and only allocates when the capacity is not enough
+func (r *RingBuffer[T]) CopyToN(dst *[]T) {
+	a, b := r.runs()
+	*dst = append(append((*dst)[:0], a...), b...)
+}
+
+// ShuffledIter returns an iterator of the buffer that iterate in a random order determined by rng
+// The buffer will not be modified, the order is shuffled each time the iteration starts
+func (r *RingBuffer[T]) ShuffledIter(rng *rand.Rand) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		indexes := make([]int, r.Len())
+		for k := range indexes {
+			indexes[k] = k
+		}
+		rng.Shuffle(len(indexes), func(a, b int) {
+			indexes[a], indexes[b] = indexes[b], indexes[a]
+		})
+		for _, k := range indexes {
+			if !yield(r.Get(k)) {
+				return
+			}
+		}
+	}
+}
+
+// AsDeque returns the elements as a newly allocated slice from first to last, and the head index of it
+// The head index is always 0, since the elements are in logical order
+func (r *RingBuffer[T]) AsDeque() ([]T, int) {
+	return r.ToSlice(), 0
+}
+
+// FromDeque creates a ring buffer with the given size, and loads deque[head:] into it
+// Only the last size elements are kept if there are more elements than size
+func FromDeque[T any](size int, deque []T, head int) *RingBuffer[T] {
+	return NewRingBufferFromSlice(size, deque[head:])
+}
+
+// ParallelForEachChunk splits the elements into roughly equal chunks, and calls fn on them concurrently
+// with at most workers goroutines, it returns after all chunks are processed
+// fn must be safe to be called concurrently on different chunks
+// The chunks share memory with the buffer, they must not be retained after fn returns,
+// and the buffer must not be modified until ParallelForEachChunk returns
+func (r *RingBuffer[T]) ParallelForEachChunk(workers int, fn func(chunk []T)) {
+	if workers < 1 {
+		panic("worker count must be greater than 0")
+	}
+	n := r.Len()
+	if n == 0 {
+		return
+	}
+	workers = min(workers, n)
+	size := (n + workers - 1) / workers
+	chunks := make(chan []T, workers+1)
+	a, b := r.runs()
+	for _, run := range [][]T{a, b} {
+		for len(run) > 0 {
+			m := min(size, len(run))
+			chunks <- run[:m]
+			run = run[m:]
+		}
+	}
+	close(chunks)
+	var wg sync.WaitGroup
+	for range min(workers, len(chunks)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for chunk := range chunks {
+				fn(chunk)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// SnapshotInto pushes all elements into archive, from first to last
+// Earlier elements in archive will be overwritten if there is no space avaliable
+func (r *RingBuffer[T]) SnapshotInto(archive *RingBuffer[T]) {
+	if archive == r {
+		r = r.Clone()
+	}
+	r.ForEach(func(v T) bool {
+		archive.Push(v)
+		return true
+	})
+}
+
+// FoldWhile folds the elements from first to last into an accumulator
+// fn returns the new accumulator and whether to continue,
+// the accumulator returned by the last call of fn is the result
+func FoldWhile[T, A any](r *RingBuffer[T], init A, fn func(acc A, v T) (A, bool)) A {
+	acc := init
+	r.ForEach(func(v T) bool {
+		var ok bool
+		acc, ok = fn(acc, v)
+		return ok
+	})
+	return acc
+}
+
+type sortAdapter[T any] struct {
+	r    *RingBuffer[T]
+	less func(i, j int) bool
+}
+
+func (s sortAdapter[T]) Len() int           { return s.r.Len() }
+func (s sortAdapter[T]) Less(i, j int) bool { return s.less(i, j) }
+func (s sortAdapter[T]) Swap(i, j int)      { s.r.swap(i, j) }
+
+// SortInterface returns a sort.Interface which sorts the buffer in place by less
+// The indexes passed to less are logical indexes, which can be used with Get
+func (r *RingBuffer[T]) SortInterface(less func(i, j int) bool) sort.Interface {
+	return sortAdapter[T]{r: r, less: less}
+}
+
+// MapField returns a slice of field(v) for each element v, from first to last
+func MapField[T any, V any](r *RingBuffer[T], field func(T) V) []V {
+	res := make([]V, 0, r.Len())
+	r.ForEach(func(v T) bool {
+		res = append(res, field(v))
+		return true
+	})
+	return res
+}
@@ -0,0 +1,130 @@
+// Ring buffer
+// Copyright (C) 2025  Kevin Z <zyxkad@gmail.com>
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package ringbuf
+
+import (
+	"testing"
+)
+
+func TestRingBufferRepair(t *testing.T) {
+	rb := NewRingBuffer[int](4)
+	for i := range 6 {
+		rb.Push(i)
+	}
+	if rb.Repair() {
+		t.Errorf("Expect no repair for a consistent buffer")
+	}
+	if got, v := rb.Len(), 4; got != v {
+		t.Errorf("Expect %d for length, got %d", v, got)
+	}
+
+	for _, corrupt := range []func(*RingBuffer[int]){
+		func(r *RingBuffer[int]) { r.i = -1 },
+		func(r *RingBuffer[int]) { r.i = 4 },
+		func(r *RingBuffer[int]) { r.j = 7 },
+		func(r *RingBuffer[int]) { r.hasElem = false; r.i, r.j = 1, 3 },
+	} {
+		rb := NewRingBuffer[int](4)
+		rb.Push(1)
+		rb.Push(2)
+		corrupt(rb)
+		if !rb.Repair() {
+			t.Errorf("Expect repair for corrupt state %d, %d, %v", rb.i, rb.j, rb.hasElem)
+			continue
+		}
+		if !rb.checkInvariants() {
+			t.Errorf("Expect consistent state after repair, got %d, %d, %v", rb.i, rb.j, rb.hasElem)
+		}
+		if got := rb.Len(); got != 0 {
+			t.Errorf("Expect 0 for length after repair, got %d", got)
+		}
+		if got := rb.Cap(); got != 4 {
+			t.Errorf("Expect 4 for capacity after repair, got %d", got)
+		}
+		for i := range 5 {
+			rb.Push(i)
+		}
+		for i, v := range []int{1, 2, 3, 4} {
+			if got := rb.Get(i); got != v {
+				t.Errorf("Expect %d at i %d after repair, got %d", v, i, got)
+			}
+		}
+	}
+
+	var zero RingBuffer[int]
+	if zero.Repair() {
+		t.Errorf("Expect no repair for a zero capacity buffer")
+	}
+	if got := zero.Cap(); got != 0 {
+		t.Errorf("Expect 0 for capacity, got %d", got)
+	}
+}
+
+func TestRingBufferClearFuncDereference(t *testing.T) {
+	rb := NewRingBuffer[*int](4)
+	for i := range 6 {
+		rb.Push(&i)
+	}
+	rb.Poll()
+	visited := 0
+	rb.ClearFunc(func(*int) { visited++ })
+	if visited != 3 {
+		t.Errorf("Expect 3 elements visited, got %d", visited)
+	}
+	for k, v := range rb.buf {
+		if v != nil {
+			t.Errorf("Expect slot %d dereferenced, got %v", k, v)
+		}
+	}
+}
+
+func TestRingBufferRemoveFuncDereference(t *testing.T) {
+	rb := NewRingBuffer[*int](4)
+	for i := range 6 {
+		rb.Push(&i)
+	}
+	// buffer: &2 &3 &4 &5, wrapped
+	rb.RemoveFunc(func(p *int) bool { return *p%2 == 0 })
+	nils := 0
+	for _, v := range rb.buf {
+		if v == nil {
+			nils++
+		}
+	}
+	if nils != 2 {
+		t.Errorf("Expect 2 slots dereferenced, got %d", nils)
+	}
+	if !rb.checkInvariants() {
+		t.Errorf("Expect invariants hold")
+	}
+}
+
+func TestRingBufferDiscardDereference(t *testing.T) {
+	rb := NewRingBuffer[*int](4)
+	for i := range 6 {
+		rb.Push(&i)
+	}
+	rb.Discard(3)
+	nils := 0
+	for _, v := range rb.buf {
+		if v == nil {
+			nils++
+		}
+	}
+	if nils != 3 {
+		t.Errorf("Expect 3 slots dereferenced, got %d", nils)
+	}
+}
@@ -16,6 +16,10 @@
 package ringbuf_test
 
 import (
+	"math"
+	"math/rand/v2"
+	"sort"
+	"sync"
 	"testing"
 
 	. "github.com/kmcsr/go-ringbuf"
@@ -97,3 +101,1523 @@ func TestRingBufferPushGetPoll(t *testing.T) {
 		t.Errorf("Expect %d for length, got %d", v, got)
 	}
 }
+
+func TestRingBufferReversed(t *testing.T) {
+	rb := NewRingBuffer[int](4)
+	for i := range 6 {
+		rb.Push(i)
+	}
+	rev := rb.Reversed()
+	if got, v := rev.Cap(), rb.Cap(); got != v {
+		t.Errorf("Expect %d for capacity, got %d", v, got)
+	}
+	if got, v := rev.Len(), rb.Len(); got != v {
+		t.Errorf("Expect %d for length, got %d", v, got)
+	}
+	if got, v := rev.Get(0), rb.Get(rb.Len()-1); got != v {
+		t.Errorf("Expect %d at i 0, got %d", v, got)
+	}
+	for i, v := range []int{5, 4, 3, 2} {
+		if got := rev.Get(i); got != v {
+			t.Errorf("Expect %d at i %d of reversed, got %d", v, i, got)
+		}
+	}
+	for i, v := range []int{2, 3, 4, 5} {
+		if got := rb.Get(i); got != v {
+			t.Errorf("Expect %d at i %d of original, got %d", v, i, got)
+		}
+	}
+}
+
+func TestRingBufferSample(t *testing.T) {
+	rb := NewRingBuffer[int](5)
+	for i := range 8 {
+		rb.Push(i)
+	}
+	// buffer: 3 4 5 6 7
+	expect := func(n int, want []int) {
+		got := rb.Sample(n)
+		if len(got) != len(want) {
+			t.Errorf("Expect %v for sample %d, got %v", want, n, got)
+			return
+		}
+		for i, v := range want {
+			if got[i] != v {
+				t.Errorf("Expect %v for sample %d, got %v", want, n, got)
+				return
+			}
+		}
+	}
+	expect(1, []int{3})
+	expect(2, []int{3, 7})
+	expect(3, []int{3, 5, 7})
+	expect(5, []int{3, 4, 5, 6, 7})
+	expect(10, []int{3, 4, 5, 6, 7})
+}
+
+func TestRingBufferHasOverflowed(t *testing.T) {
+	rb := NewRingBuffer[int](3)
+	for i := range 3 {
+		rb.Push(i)
+		if rb.HasOverflowed() {
+			t.Errorf("Expect not overflowed after %d pushes", i+1)
+		}
+	}
+	rb.Push(3)
+	if !rb.HasOverflowed() {
+		t.Errorf("Expect overflowed after the first eviction")
+	}
+	rb.Poll()
+	if !rb.HasOverflowed() {
+		t.Errorf("Expect overflowed to stay set after poll")
+	}
+	rb.ClearOverflowed()
+	if rb.HasOverflowed() {
+		t.Errorf("Expect not overflowed after ClearOverflowed")
+	}
+	rb.Push(4)
+	if rb.HasOverflowed() {
+		t.Errorf("Expect not overflowed when there is free space")
+	}
+	rb.Push(5)
+	if !rb.HasOverflowed() {
+		t.Errorf("Expect overflowed after eviction")
+	}
+	rb.Clear()
+	if rb.HasOverflowed() {
+		t.Errorf("Expect not overflowed after Clear")
+	}
+	for i := range 4 {
+		rb.Push(i)
+	}
+	rb.Reset()
+	if rb.HasOverflowed() {
+		t.Errorf("Expect not overflowed after Reset")
+	}
+}
+
+func TestRingBufferRotate(t *testing.T) {
+	for _, c := range []struct {
+		n    int
+		want []int
+	}{
+		{0, []int{3, 4, 5, 6, 7}},
+		{1, []int{4, 5, 6, 7, 3}},
+		{2, []int{5, 6, 7, 3, 4}},
+		{-1, []int{7, 3, 4, 5, 6}},
+		{-7, []int{6, 7, 3, 4, 5}},
+		{5, []int{3, 4, 5, 6, 7}},
+		{13, []int{6, 7, 3, 4, 5}},
+	} {
+		rb := NewRingBuffer[int](6)
+		for i := range 8 {
+			rb.Push(i)
+		}
+		rb.Poll()
+		// buffer: 3 4 5 6 7, wrapped
+		rb.Rotate(c.n)
+		if got := rb.Len(); got != len(c.want) {
+			t.Errorf("Expect %d for length after rotate %d, got %d", len(c.want), c.n, got)
+			continue
+		}
+		for i, v := range c.want {
+			if got := rb.Get(i); got != v {
+				t.Errorf("Expect %d at i %d after rotate %d, got %d", v, i, c.n, got)
+			}
+		}
+	}
+}
+
+func TestRingBufferRotateFull(t *testing.T) {
+	for _, n := range []int{0, 1, 3, 4, 5, -1, -4, -6, 9, 23, -17} {
+		rb := NewRingBuffer[int](5)
+		for i := range 8 {
+			rb.Push(i)
+		}
+		// buffer: 3 4 5 6 7, full and wrapped
+		old := rb.ToSlice()
+		rb.Rotate(n)
+		if !rb.Full() {
+			t.Errorf("Expect buffer full after rotate %d", n)
+		}
+		k := ((n % 5) + 5) % 5
+		for i := range 5 {
+			if got, want := rb.Get(i), old[(i+k)%5]; got != want {
+				t.Errorf("Expect %d at i %d after rotate %d, got %d", want, i, n, got)
+			}
+		}
+		rb.Push(8)
+		if got, want := rb.Get(4), 8; got != want {
+			t.Errorf("Expect %d at i 4 after rotate %d and push, got %d", want, n, got)
+		}
+		if got, want := rb.Get(0), old[(k+1)%5]; got != want {
+			t.Errorf("Expect %d at i 0 after rotate %d and push, got %d", want, n, got)
+		}
+	}
+}
+
+func TestInterleave(t *testing.T) {
+	a := NewRingBuffer[int](4)
+	b := NewRingBuffer[int](4)
+	for i := range 3 {
+		a.Push(i)
+		b.Push(i + 10)
+	}
+	expect := func(rb *RingBuffer[int], want []int) {
+		if got := rb.Len(); got != len(want) {
+			t.Errorf("Expect %d for length, got %d", len(want), got)
+			return
+		}
+		for i, v := range want {
+			if got := rb.Get(i); got != v {
+				t.Errorf("Expect %d at i %d, got %d", v, i, got)
+			}
+		}
+	}
+	expect(Interleave(a, b, 8), []int{0, 10, 1, 11, 2, 12})
+	b.Push(13)
+	b.Push(14)
+	// b: 11 12 13 14
+	expect(Interleave(a, b, 8), []int{0, 11, 1, 12, 2, 13})
+	expect(Interleave(b, a, 8), []int{11, 0, 12, 1, 13, 2})
+	expect(Interleave(a, b, 4), []int{1, 12, 2, 13})
+}
+
+func TestRingBufferSlidingWindowsIter(t *testing.T) {
+	rb := NewRingBuffer[int](5)
+	for i := range 7 {
+		rb.Push(i)
+	}
+	// buffer: 2 3 4 5 6
+	for _, width := range []int{1, 2, 3, 5, 6} {
+		count := 0
+		for window := range rb.SlidingWindowsIter(width) {
+			if len(window) != width {
+				t.Errorf("Expect %d for window length, got %d", width, len(window))
+				continue
+			}
+			for k, v := range window {
+				if want := rb.Get(count + k); v != want {
+					t.Errorf("Expect %d at i %d of window %d with width %d, got %d", want, k, count, width, v)
+				}
+			}
+			count++
+		}
+		if want := max(rb.Len()-width+1, 0); count != want {
+			t.Errorf("Expect %d windows with width %d, got %d", want, width, count)
+		}
+	}
+	count := 0
+	for range rb.SlidingWindowsIter(2) {
+		count++
+		if count == 2 {
+			break
+		}
+	}
+	if count != 2 {
+		t.Errorf("Expect iteration to stop after break, got %d windows", count)
+	}
+}
+
+func TestRingBufferPopFrontWhile(t *testing.T) {
+	rb := NewRingBuffer[int](5)
+	for _, v := range []int{1, 2, 4, 6, 7, 8, 9} {
+		rb.Push(v)
+	}
+	// buffer: 4 6 7 8 9
+	even := func(v int) bool { return v%2 == 0 }
+	got := rb.PopFrontWhile(even)
+	if len(got) != 2 || got[0] != 4 || got[1] != 6 {
+		t.Errorf("Expect [4 6] popped, got %v", got)
+	}
+	if got := rb.PopFrontWhile(even); len(got) != 0 {
+		t.Errorf("Expect nothing popped, got %v", got)
+	}
+	for i, v := range []int{7, 8, 9} {
+		if got := rb.Get(i); got != v {
+			t.Errorf("Expect %d at i %d, got %d", v, i, got)
+		}
+	}
+	if got := rb.PopFrontWhile(func(int) bool { return true }); len(got) != 3 {
+		t.Errorf("Expect all elements popped, got %v", got)
+	}
+	if got := rb.Len(); got != 0 {
+		t.Errorf("Expect 0 for length, got %d", got)
+	}
+}
+
+func TestRingBufferPopBackWhile(t *testing.T) {
+	rb := NewRingBuffer[int](5)
+	for _, v := range []int{1, 2, 3, 5, 7, 0, 0} {
+		rb.Push(v)
+	}
+	// buffer: 3 5 7 0 0
+	zero := func(v int) bool { return v == 0 }
+	if got := rb.PopBackWhile(zero); len(got) != 2 || got[0] != 0 || got[1] != 0 {
+		t.Errorf("Expect [0 0] popped, got %v", got)
+	}
+	if got := rb.PopBackWhile(zero); len(got) != 0 {
+		t.Errorf("Expect nothing popped, got %v", got)
+	}
+	if got := rb.PopBackWhile(func(v int) bool { return v > 4 }); len(got) != 2 || got[0] != 5 || got[1] != 7 {
+		t.Errorf("Expect [5 7] popped, got %v", got)
+	}
+	if got, v := rb.Len(), 1; got != v {
+		t.Errorf("Expect %d for length, got %d", v, got)
+	}
+	if got := rb.Get(0); got != 3 {
+		t.Errorf("Expect %d at i %d, got %d", 3, 0, got)
+	}
+	rb.Push(4)
+	if got := rb.Get(1); got != 4 {
+		t.Errorf("Expect %d at i %d, got %d", 4, 1, got)
+	}
+	if got := rb.PopBackWhile(func(int) bool { return true }); len(got) != 2 || got[0] != 3 || got[1] != 4 {
+		t.Errorf("Expect [3 4] popped, got %v", got)
+	}
+	if got := rb.Len(); got != 0 {
+		t.Errorf("Expect 0 for length, got %d", got)
+	}
+}
+
+func TestRingBufferSize(t *testing.T) {
+	rb := NewRingBuffer[int](3)
+	for i := range 5 {
+		if l, c := rb.Size(); l != rb.Len() || c != rb.Cap() {
+			t.Errorf("Expect %d, %d for size, got %d, %d", rb.Len(), rb.Cap(), l, c)
+		}
+		rb.Push(i)
+	}
+	if l, c := rb.Size(); l != 3 || c != 3 {
+		t.Errorf("Expect 3, 3 for size, got %d, %d", l, c)
+	}
+}
+
+func TestRingBufferNextEvict(t *testing.T) {
+	rb := NewRingBuffer[int](3)
+	if _, ok := rb.NextEvict(); ok {
+		t.Errorf("Expect not ok for empty buffer")
+	}
+	rb.Push(1)
+	rb.Push(2)
+	if _, ok := rb.NextEvict(); ok {
+		t.Errorf("Expect not ok when there is free space")
+	}
+	rb.Push(3)
+	if got, ok := rb.NextEvict(); !ok || got != 1 {
+		t.Errorf("Expect 1 for next evict, got %d, %v", got, ok)
+	}
+	rb.Push(4)
+	if got, ok := rb.NextEvict(); !ok || got != 2 {
+		t.Errorf("Expect 2 for next evict, got %d, %v", got, ok)
+	}
+	rb.Poll()
+	if _, ok := rb.NextEvict(); ok {
+		t.Errorf("Expect not ok after poll")
+	}
+}
+
+func TestCoalesce(t *testing.T) {
+	type event struct {
+		name  string
+		count int
+	}
+	rb := NewRingBuffer[event](6)
+	for _, e := range []event{{"x", 1}, {"x", 1}, {"a", 1}, {"a", 2}, {"b", 1}, {"a", 1}, {"c", 1}, {"c", 3}} {
+		rb.Push(e)
+	}
+	// buffer: a1 a2 b1 a1 c1 c3
+	Coalesce(rb, func(a, b event) bool {
+		return a.name == b.name
+	}, func(a, b event) event {
+		return event{a.name, a.count + b.count}
+	})
+	want := []event{{"a", 3}, {"b", 1}, {"a", 1}, {"c", 4}}
+	if got := rb.Len(); got != len(want) {
+		t.Fatalf("Expect %d for length, got %d", len(want), got)
+	}
+	for i, v := range want {
+		if got := rb.Get(i); got != v {
+			t.Errorf("Expect %v at i %d, got %v", v, i, got)
+		}
+	}
+	rb.Push(event{"d", 1})
+	if got := rb.Get(4); got != (event{"d", 1}) {
+		t.Errorf("Expect %v at i %d, got %v", event{"d", 1}, 4, got)
+	}
+
+	// merged elements may become mergeable with their previous element
+	nums := NewRingBuffer[int](5)
+	for _, v := range []int{5, 1, 1, 3} {
+		nums.Push(v)
+	}
+	Coalesce(nums, func(a, b int) bool {
+		return a <= b
+	}, func(a, b int) int {
+		return a + b
+	})
+	if got := nums.Len(); got != 1 {
+		t.Fatalf("Expect 1 for length, got %d", got)
+	}
+	if got := nums.Get(0); got != 10 {
+		t.Errorf("Expect %d at i %d, got %d", 10, 0, got)
+	}
+}
+
+func TestRingBufferCopyToN(t *testing.T) {
+	rb := NewRingBuffer[int](4)
+	dst := make([]int, 0, 4)
+	rb.CopyToN(&dst)
+	if len(dst) != 0 {
+		t.Errorf("Expect empty result for empty buffer, got %v", dst)
+	}
+	for i := range 6 {
+		rb.Push(i)
+	}
+	dst = append(dst, 9, 9, 9, 9)
+	rb.CopyToN(&dst)
+	if len(dst) != 4 {
+		t.Fatalf("Expect 4 elements, got %v", dst)
+	}
+	for i, v := range []int{2, 3, 4, 5} {
+		if dst[i] != v {
+			t.Errorf("Expect %d at i %d, got %d", v, i, dst[i])
+		}
+	}
+	if allocs := testing.AllocsPerRun(100, func() { rb.CopyToN(&dst) }); allocs != 0 {
+		t.Errorf("Expect no allocation, got %v", allocs)
+	}
+}
+
+func BenchmarkRingBufferCopyToN(b *testing.B) {
+	rb := NewRingBuffer[int](1024)
+	for i := range 1500 {
+		rb.Push(i)
+	}
+	dst := make([]int, 0, rb.Cap())
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		rb.CopyToN(&dst)
+	}
+}
+
+func TestRingBufferShuffledIter(t *testing.T) {
+	rb := NewRingBuffer[int](8)
+	for i := range 12 {
+		rb.Push(i)
+	}
+	collect := func(seed uint64) []int {
+		var res []int
+		for v := range rb.ShuffledIter(rand.New(rand.NewPCG(seed, seed))) {
+			res = append(res, v)
+		}
+		return res
+	}
+	a, b := collect(1), collect(1)
+	if len(a) != rb.Len() || len(b) != rb.Len() {
+		t.Fatalf("Expect %d elements, got %v and %v", rb.Len(), a, b)
+	}
+	seen := make(map[int]bool)
+	for i, v := range a {
+		if v != b[i] {
+			t.Errorf("Expect the same order for the same seed, got %v and %v", a, b)
+			break
+		}
+		if v < 4 || v >= 12 || seen[v] {
+			t.Errorf("Expect each element appears exactly once, got %v", a)
+			break
+		}
+		seen[v] = true
+	}
+	for i, v := range []int{4, 5, 6, 7, 8, 9, 10, 11} {
+		if got := rb.Get(i); got != v {
+			t.Errorf("Expect %d at i %d, got %d", v, i, got)
+		}
+	}
+}
+
+func TestRingBufferDeque(t *testing.T) {
+	rb := NewRingBuffer[int](5)
+	for i := range 7 {
+		rb.Push(i)
+	}
+	rb.Poll()
+	deque, head := rb.AsDeque()
+	if head != 0 {
+		t.Errorf("Expect 0 for head, got %d", head)
+	}
+	for i, v := range []int{3, 4, 5, 6} {
+		if deque[i] != v {
+			t.Errorf("Expect %d at i %d of deque, got %d", v, i, deque[i])
+		}
+	}
+	deque = append(deque, 7)
+	rb2 := FromDeque(rb.Cap(), deque, 1)
+	if got, v := rb2.Len(), 4; got != v {
+		t.Errorf("Expect %d for length, got %d", v, got)
+	}
+	if got, v := rb2.Cap(), 5; got != v {
+		t.Errorf("Expect %d for capacity, got %d", v, got)
+	}
+	for i, v := range []int{4, 5, 6, 7} {
+		if got := rb2.Get(i); got != v {
+			t.Errorf("Expect %d at i %d, got %d", v, i, got)
+		}
+	}
+	deque[1] = 100
+	if got := rb2.Get(0); got != 4 {
+		t.Errorf("Expect the buffer not share memory with deque, got %d", got)
+	}
+
+	rb3 := FromDeque(2, deque, 0)
+	if got, v := rb3.Len(), 2; got != v {
+		t.Errorf("Expect %d for length, got %d", v, got)
+	}
+	for i, v := range []int{6, 7} {
+		if got := rb3.Get(i); got != v {
+			t.Errorf("Expect %d at i %d, got %d", v, i, got)
+		}
+	}
+	rb3.Push(8)
+	if got := rb3.Get(1); got != 8 {
+		t.Errorf("Expect %d at i %d, got %d", 8, 1, got)
+	}
+}
+
+func TestRingBufferParallelForEachChunk(t *testing.T) {
+	rb := NewRingBuffer[int](100)
+	for i := range 130 {
+		rb.Push(i)
+	}
+	for _, workers := range []int{1, 3, 4, 200} {
+		var (
+			mux    sync.Mutex
+			sums   []int
+			chunks int
+		)
+		rb.ParallelForEachChunk(workers, func(chunk []int) {
+			sum := 0
+			for _, v := range chunk {
+				sum += v
+			}
+			mux.Lock()
+			sums = append(sums, sum)
+			chunks++
+			mux.Unlock()
+		})
+		total := 0
+		for _, s := range sums {
+			total += s
+		}
+		if want := (30 + 129) * 100 / 2; total != want {
+			t.Errorf("Expect %d for total with %d workers, got %d", want, workers, total)
+		}
+		if chunks > workers+1 {
+			t.Errorf("Expect at most %d chunks with %d workers, got %d", workers+1, workers, chunks)
+		}
+	}
+	rb.ParallelForEachChunk(4, func(chunk []int) {
+		for i := range chunk {
+			chunk[i] *= 2
+		}
+	})
+	for i := range rb.Len() {
+		if got, v := rb.Get(i), (i+30)*2; got != v {
+			t.Errorf("Expect %d at i %d, got %d", v, i, got)
+		}
+	}
+
+	small := NewRingBuffer[int](4)
+	small.Push(1)
+	for _, workers := range []int{1 << 40, math.MaxInt} {
+		calls := 0
+		small.ParallelForEachChunk(workers, func(chunk []int) {
+			calls++
+		})
+		if calls != 1 {
+			t.Errorf("Expect 1 chunk with %d workers, got %d", workers, calls)
+		}
+	}
+}
+
+func TestRingBufferSnapshotInto(t *testing.T) {
+	rb := NewRingBuffer[int](3)
+	archive := NewRingBuffer[int](8)
+	for i := range 4 {
+		rb.Push(i)
+	}
+	rb.SnapshotInto(archive)
+	rb.Push(4)
+	rb.Push(5)
+	rb.SnapshotInto(archive)
+	for i, v := range []int{1, 2, 3, 3, 4, 5} {
+		if got := archive.Get(i); got != v {
+			t.Errorf("Expect %d at i %d, got %d", v, i, got)
+		}
+	}
+	rb.SnapshotInto(archive)
+	for i, v := range []int{2, 3, 3, 4, 5, 3, 4, 5} {
+		if got := archive.Get(i); got != v {
+			t.Errorf("Expect %d at i %d, got %d", v, i, got)
+		}
+	}
+	if got, v := archive.Len(), 8; got != v {
+		t.Errorf("Expect %d for length, got %d", v, got)
+	}
+	if got, v := rb.Len(), 3; got != v {
+		t.Errorf("Expect %d for length, got %d", v, got)
+	}
+
+	self := NewRingBuffer[int](5)
+	self.Push(1)
+	self.Push(2)
+	self.SnapshotInto(self)
+	want := []int{1, 2, 1, 2}
+	if got := self.Len(); got != len(want) {
+		t.Errorf("Expect %d for length after snapshot into itself, got %d", len(want), got)
+	}
+	for i, v := range want {
+		if got := self.Get(i); got != v {
+			t.Errorf("Expect %d at i %d after snapshot into itself, got %d", v, i, got)
+		}
+	}
+}
+
+func TestRingBufferPeek(t *testing.T) {
+	rb := NewRingBuffer[int](3)
+	if _, ok := rb.Peek(); ok {
+		t.Errorf("Expect not ok when peek empty buffer")
+	}
+	if _, ok := rb.PeekBack(); ok {
+		t.Errorf("Expect not ok when peek back empty buffer")
+	}
+	for i := 1; i <= 5; i++ {
+		rb.Push(i)
+		if got, ok := rb.Peek(); !ok || got != max(1, i-2) {
+			t.Errorf("Expect %d when peek, got %d, %v", max(1, i-2), got, ok)
+		}
+		if got, ok := rb.PeekBack(); !ok || got != i {
+			t.Errorf("Expect %d when peek back, got %d, %v", i, got, ok)
+		}
+	}
+	if got, v := rb.Len(), 3; got != v {
+		t.Errorf("Expect %d for length, got %d", v, got)
+	}
+	rb.Poll()
+	rb.Poll()
+	if got, ok := rb.Peek(); !ok || got != 5 {
+		t.Errorf("Expect %d when peek, got %d, %v", 5, got, ok)
+	}
+	if got, ok := rb.PeekBack(); !ok || got != 5 {
+		t.Errorf("Expect %d when peek back, got %d, %v", 5, got, ok)
+	}
+	rb.Poll()
+	if _, ok := rb.Peek(); ok {
+		t.Errorf("Expect not ok when peek empty buffer")
+	}
+	if _, ok := rb.PeekBack(); ok {
+		t.Errorf("Expect not ok when peek back empty buffer")
+	}
+}
+
+func TestRingBufferFullIsEmpty(t *testing.T) {
+	rb := NewRingBuffer[int](3)
+	expect := func(full, empty bool) {
+		t.Helper()
+		if got := rb.Full(); got != full {
+			t.Errorf("Expect %v for full with length %d, got %v", full, rb.Len(), got)
+		}
+		if got := rb.IsEmpty(); got != empty {
+			t.Errorf("Expect %v for empty with length %d, got %v", empty, rb.Len(), got)
+		}
+	}
+	expect(false, true)
+	rb.Push(1)
+	expect(false, false)
+	rb.Push(2)
+	rb.Push(3)
+	expect(true, false)
+	rb.Push(4)
+	expect(true, false)
+	rb.Poll()
+	expect(false, false)
+	rb.Poll()
+	rb.Poll()
+	expect(false, true)
+}
+
+func TestRingBufferPushSafe(t *testing.T) {
+	rb := NewRingBuffer[int](3)
+	for i := range 3 {
+		if !rb.PushSafe(i) {
+			t.Errorf("Expect push %d succeed", i)
+		}
+	}
+	if rb.PushSafe(3) {
+		t.Errorf("Expect push fail when buffer is full")
+	}
+	for i, v := range []int{0, 1, 2} {
+		if got := rb.Get(i); got != v {
+			t.Errorf("Expect %d at i %d, got %d", v, i, got)
+		}
+	}
+	if rb.HasOverflowed() {
+		t.Errorf("Expect not overflowed")
+	}
+	rb.Poll()
+	if !rb.PushSafe(3) {
+		t.Errorf("Expect push succeed after poll")
+	}
+	for i, v := range []int{1, 2, 3} {
+		if got := rb.Get(i); got != v {
+			t.Errorf("Expect %d at i %d, got %d", v, i, got)
+		}
+	}
+}
+
+func TestRingBufferReplaceNewest(t *testing.T) {
+	rb := NewRingBuffer[int](3)
+	if rb.ReplaceNewest(1) {
+		t.Errorf("Expect replace fail on empty buffer")
+	}
+	for i := range 3 {
+		rb.Push(i)
+	}
+	if !rb.ReplaceNewest(9) {
+		t.Errorf("Expect replace succeed")
+	}
+	for i, v := range []int{0, 1, 9} {
+		if got := rb.Get(i); got != v {
+			t.Errorf("Expect %d at i %d, got %d", v, i, got)
+		}
+	}
+	rb.Push(3)
+	rb.Push(4)
+	rb.ReplaceNewest(8)
+	for i, v := range []int{9, 3, 8} {
+		if got := rb.Get(i); got != v {
+			t.Errorf("Expect %d at i %d, got %d", v, i, got)
+		}
+	}
+	if got, v := rb.Len(), 3; got != v {
+		t.Errorf("Expect %d for length, got %d", v, got)
+	}
+}
+
+func TestRingBufferPushSlice(t *testing.T) {
+	for _, c := range [][]int{
+		{},
+		{1},
+		{1, 2},
+		{1, 2, 3},
+		{1, 2, 3, 4},
+		{1, 2, 3, 4, 5},
+		{1, 2, 3, 4, 5, 6, 7, 8, 9},
+	} {
+		for start := range 6 {
+			rb := NewRingBuffer[int](4)
+			expect := NewRingBuffer[int](4)
+			for i := range start {
+				rb.Push(-i)
+				expect.Push(-i)
+			}
+			if start%2 == 1 {
+				rb.Poll()
+				expect.Poll()
+			}
+			rb.PushSlice(c)
+			for _, v := range c {
+				expect.Push(v)
+			}
+			if got, v := rb.Len(), expect.Len(); got != v {
+				t.Errorf("Expect %d for length when push %v after %d, got %d", v, c, start, got)
+				continue
+			}
+			if got, v := rb.HasOverflowed(), expect.HasOverflowed(); got != v {
+				t.Errorf("Expect %v for overflowed when push %v after %d, got %v", v, c, start, got)
+			}
+			for i := range rb.Len() {
+				if got, v := rb.Get(i), expect.Get(i); got != v {
+					t.Errorf("Expect %d at i %d when push %v after %d, got %d", v, i, c, start, got)
+				}
+			}
+			rb.Push(100)
+			if got, ok := rb.PeekBack(); !ok || got != 100 {
+				t.Errorf("Expect %d when peek back, got %d", 100, got)
+			}
+		}
+	}
+}
+
+func TestRingBufferPollN(t *testing.T) {
+	rb := NewRingBuffer[int](5)
+	for i := range 8 {
+		rb.Push(i)
+	}
+	// buffer: 3 4 5 6 7, wrapped
+	dst := make([]int, 3)
+	if n := rb.PollN(dst); n != 3 {
+		t.Errorf("Expect 3 polled, got %d", n)
+	}
+	for i, v := range []int{3, 4, 5} {
+		if dst[i] != v {
+			t.Errorf("Expect %d at i %d of dst, got %d", v, i, dst[i])
+		}
+	}
+	if got, v := rb.Len(), 2; got != v {
+		t.Errorf("Expect %d for length, got %d", v, got)
+	}
+	rb.Push(8)
+	dst = make([]int, 10)
+	if n := rb.PollN(dst); n != 3 {
+		t.Errorf("Expect 3 polled, got %d", n)
+	}
+	for i, v := range []int{6, 7, 8} {
+		if dst[i] != v {
+			t.Errorf("Expect %d at i %d of dst, got %d", v, i, dst[i])
+		}
+	}
+	if !rb.IsEmpty() {
+		t.Errorf("Expect empty buffer, got length %d", rb.Len())
+	}
+	if n := rb.PollN(dst); n != 0 {
+		t.Errorf("Expect 0 polled, got %d", n)
+	}
+	if n := rb.PollN(nil); n != 0 {
+		t.Errorf("Expect 0 polled, got %d", n)
+	}
+	rb.Push(9)
+	rb.Push(10)
+	if got := rb.Get(1); got != 10 {
+		t.Errorf("Expect %d at i %d, got %d", 10, 1, got)
+	}
+}
+
+func TestFoldWhile(t *testing.T) {
+	rb := NewRingBuffer[int](5)
+	for i := range 7 {
+		rb.Push(i)
+	}
+	// buffer: 2 3 4 5 6
+	visited := 0
+	got := FoldWhile(rb, 0, func(acc int, v int) (int, bool) {
+		visited++
+		acc += v
+		return acc, acc < 8
+	})
+	if got != 9 || visited != 3 {
+		t.Errorf("Expect 9 after visiting 3 elements, got %d after %d", got, visited)
+	}
+	if got := FoldWhile(rb, "", func(acc string, v int) (string, bool) {
+		return acc + string(rune('0'+v)), true
+	}); got != "23456" {
+		t.Errorf("Expect %q, got %q", "23456", got)
+	}
+	if got := FoldWhile(NewRingBuffer[int](1), 7, func(acc int, v int) (int, bool) {
+		return 0, false
+	}); got != 7 {
+		t.Errorf("Expect the initial value for empty buffer, got %d", got)
+	}
+}
+
+func TestRingBufferClearFunc(t *testing.T) {
+	type session struct{ id int }
+	rb := NewRingBuffer[*session](4)
+	for i := range 6 {
+		rb.Push(&session{i})
+	}
+	rb.Poll()
+	// buffer: 3 4 5
+	var released []int
+	rb.ClearFunc(func(s *session) {
+		released = append(released, s.id)
+	})
+	if len(released) != 3 || released[0] != 3 || released[1] != 4 || released[2] != 5 {
+		t.Errorf("Expect [3 4 5] released, got %v", released)
+	}
+	if !rb.IsEmpty() {
+		t.Errorf("Expect empty buffer, got length %d", rb.Len())
+	}
+	rb.Push(&session{10})
+	if got, ok := rb.Peek(); !ok || got.id != 10 {
+		t.Errorf("Expect the new element after clear, got %v", got)
+	}
+	released = released[:0]
+	NewRingBuffer[*session](2).ClearFunc(func(s *session) {
+		released = append(released, s.id)
+	})
+	if len(released) != 0 {
+		t.Errorf("Expect nothing released for empty buffer, got %v", released)
+	}
+}
+
+func TestRingBufferToSlice(t *testing.T) {
+	rb := NewRingBuffer[int](4)
+	if got := rb.ToSlice(); got == nil || len(got) != 0 {
+		t.Errorf("Expect empty non-nil slice for empty buffer, got %#v", got)
+	}
+	for i := range 3 {
+		rb.Push(i)
+		got := rb.ToSlice()
+		if len(got) != i+1 {
+			t.Errorf("Expect %d elements, got %v", i+1, got)
+		}
+	}
+	for i := 3; i < 7; i++ {
+		rb.Push(i)
+	}
+	rb.Poll()
+	// buffer: 4 5 6, wrapped
+	got := rb.ToSlice()
+	want := []int{4, 5, 6}
+	if len(got) != len(want) {
+		t.Fatalf("Expect %v, got %v", want, got)
+	}
+	for i, v := range want {
+		if got[i] != v {
+			t.Errorf("Expect %d at i %d, got %d", v, i, got[i])
+		}
+	}
+	got[0] = 100
+	if got := rb.Get(0); got != 4 {
+		t.Errorf("Expect the slice not share memory with buffer, got %d", got)
+	}
+}
+
+func TestRingBufferSortInterface(t *testing.T) {
+	rb := NewRingBuffer[int](6)
+	for _, v := range []int{0, 0, 5, 2, 9, 1, 5, 6} {
+		rb.Push(v)
+	}
+	// buffer: 5 2 9 1 5 6, wrapped
+	sort.Sort(rb.SortInterface(func(i, j int) bool {
+		return rb.Get(i) > rb.Get(j)
+	}))
+	for i, v := range []int{9, 6, 5, 5, 2, 1} {
+		if got := rb.Get(i); got != v {
+			t.Errorf("Expect %d at i %d, got %d", v, i, got)
+		}
+	}
+	rb.Poll()
+	rb.Push(3)
+	sort.Stable(rb.SortInterface(func(i, j int) bool {
+		return rb.Get(i)%2 < rb.Get(j)%2
+	}))
+	for i, v := range []int{6, 2, 5, 5, 1, 3} {
+		if got := rb.Get(i); got != v {
+			t.Errorf("Expect %d at i %d, got %d", v, i, got)
+		}
+	}
+}
+
+func TestNewRingBufferFromSlice(t *testing.T) {
+	for _, c := range []struct {
+		size    int
+		initial []int
+		want    []int
+	}{
+		{3, nil, []int{}},
+		{3, []int{1, 2}, []int{1, 2}},
+		{3, []int{1, 2, 3}, []int{1, 2, 3}},
+		{3, []int{1, 2, 3, 4, 5}, []int{3, 4, 5}},
+	} {
+		rb := NewRingBufferFromSlice(c.size, c.initial)
+		if got := rb.Cap(); got != c.size {
+			t.Errorf("Expect %d for capacity, got %d", c.size, got)
+		}
+		if got := rb.Len(); got != len(c.want) {
+			t.Errorf("Expect %d for length, got %d", len(c.want), got)
+			continue
+		}
+		for i, v := range c.want {
+			if got := rb.Get(i); got != v {
+				t.Errorf("Expect %d at i %d, got %d", v, i, got)
+			}
+		}
+		rb.Push(10)
+		if got, ok := rb.PeekBack(); !ok || got != 10 {
+			t.Errorf("Expect %d when peek back, got %d", 10, got)
+		}
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expect panic for size 0")
+		}
+	}()
+	NewRingBufferFromSlice(0, []int{1})
+}
+
+func TestRingBufferClone(t *testing.T) {
+	rb := NewRingBuffer[int](4)
+	for i := range 6 {
+		rb.Push(i)
+	}
+	rb.Poll()
+	// buffer: 3 4 5
+	clone := rb.Clone()
+	if got, v := clone.Cap(), rb.Cap(); got != v {
+		t.Errorf("Expect %d for capacity, got %d", v, got)
+	}
+	if got, v := clone.HasOverflowed(), rb.HasOverflowed(); got != v {
+		t.Errorf("Expect %v for overflowed, got %v", v, got)
+	}
+	clone.Push(6)
+	clone.Push(7)
+	clone.ReplaceNewest(8)
+	if got, v := clone.Len(), 4; got != v {
+		t.Errorf("Expect %d for length of clone, got %d", v, got)
+	}
+	for i, v := range []int{4, 5, 6, 8} {
+		if got := clone.Get(i); got != v {
+			t.Errorf("Expect %d at i %d of clone, got %d", v, i, got)
+		}
+	}
+	if got, v := rb.Len(), 3; got != v {
+		t.Errorf("Expect %d for length of original, got %d", v, got)
+	}
+	for i, v := range []int{3, 4, 5} {
+		if got := rb.Get(i); got != v {
+			t.Errorf("Expect %d at i %d of original, got %d", v, i, got)
+		}
+	}
+}
+
+func TestRingBufferDequeOperations(t *testing.T) {
+	rb := NewRingBuffer[int](4)
+	var model []int
+	check := func(op string) {
+		t.Helper()
+		if got := rb.Len(); got != len(model) {
+			t.Fatalf("Expect %d for length after %s, got %d", len(model), op, got)
+		}
+		for i, v := range model {
+			if got := rb.Get(i); got != v {
+				t.Errorf("Expect %d at i %d after %s, got %d", v, i, op, got)
+			}
+		}
+	}
+	if _, ok := rb.PollBack(); ok {
+		t.Errorf("Expect not ok when poll back empty buffer")
+	}
+	rb.PushFront(1)
+	model = []int{1}
+	check("push front")
+	for i := range 30 {
+		switch i % 5 {
+		case 0, 3:
+			rb.Push(i)
+			model = append(model, i)
+			if len(model) > 4 {
+				model = model[1:]
+			}
+			check("push")
+		case 1:
+			rb.PushFront(i)
+			model = append([]int{i}, model...)
+			if len(model) > 4 {
+				model = model[:4]
+			}
+			check("push front")
+		case 2:
+			v, ok := rb.PollBack()
+			if !ok || v != model[len(model)-1] {
+				t.Errorf("Expect %d when poll back, got %d, %v", model[len(model)-1], v, ok)
+			}
+			model = model[:len(model)-1]
+			check("poll back")
+		case 4:
+			v, ok := rb.Poll()
+			if !ok || v != model[0] {
+				t.Errorf("Expect %d when poll, got %d, %v", model[0], v, ok)
+			}
+			model = model[1:]
+			check("poll")
+		}
+	}
+	for range 4 {
+		rb.PushFront(-1)
+	}
+	for rb.Len() > 0 {
+		if v, _ := rb.PollBack(); v != -1 {
+			t.Errorf("Expect -1 when poll back, got %d", v)
+		}
+	}
+	if !rb.IsEmpty() {
+		t.Errorf("Expect empty buffer")
+	}
+	if !rb.HasOverflowed() {
+		t.Errorf("Expect overflowed after push front on a full buffer")
+	}
+}
+
+func TestRingBufferSet(t *testing.T) {
+	rb := NewRingBuffer[int](4)
+	for i := range 6 {
+		rb.Push(i)
+	}
+	rb.Poll()
+	// buffer: 3 4 5, wrapped
+	rb.Set(0, 30)
+	rb.Set(2, 50)
+	for i, v := range []int{30, 4, 50} {
+		if got := rb.Get(i); got != v {
+			t.Errorf("Expect %d at i %d, got %d", v, i, got)
+		}
+	}
+	for _, index := range []int{-1, 3, 4} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expect panic when set at i %d", index)
+				}
+			}()
+			rb.Set(index, 0)
+		}()
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("Expect panic when set on empty buffer")
+			}
+		}()
+		NewRingBuffer[int](1).Set(0, 0)
+	}()
+}
+
+func TestRingBufferGetFullWrapped(t *testing.T) {
+	for pushes := 3; pushes <= 7; pushes++ {
+		rb := NewRingBuffer[int](3)
+		for i := range pushes {
+			rb.Push(i)
+		}
+		for i := range rb.Cap() {
+			if got, v := rb.Get(i), pushes-3+i; got != v {
+				t.Errorf("Expect %d at i %d after %d pushes, got %d", v, i, pushes, got)
+			}
+		}
+		for _, index := range []int{-1, 3, 5} {
+			func() {
+				defer func() {
+					if recover() == nil {
+						t.Errorf("Expect panic when get at i %d after %d pushes", index, pushes)
+					}
+				}()
+				rb.Get(index)
+			}()
+		}
+	}
+}
+
+func TestRingBufferResize(t *testing.T) {
+	expect := func(rb *RingBuffer[int], want []int) {
+		t.Helper()
+		if got := rb.Len(); got != len(want) {
+			t.Fatalf("Expect %d for length, got %d", len(want), got)
+		}
+		for i, v := range want {
+			if got := rb.Get(i); got != v {
+				t.Errorf("Expect %d at i %d, got %d", v, i, got)
+			}
+		}
+		i := 0
+		rb.ForEach(func(v int) bool {
+			if v != want[i] {
+				t.Errorf("Expect %d at i %d when iterate, got %d", want[i], i, v)
+			}
+			i++
+			return true
+		})
+	}
+	rb := NewRingBuffer[int](4)
+	for i := range 6 {
+		rb.Push(i)
+	}
+	rb.Poll()
+	// buffer: 3 4 5, wrapped
+	rb.Resize(6)
+	if got := rb.Cap(); got != 6 {
+		t.Errorf("Expect 6 for capacity, got %d", got)
+	}
+	expect(rb, []int{3, 4, 5})
+	rb.Push(6)
+	rb.Push(7)
+	rb.Push(8)
+	expect(rb, []int{3, 4, 5, 6, 7, 8})
+	rb.ClearOverflowed()
+	rb.Resize(6)
+	expect(rb, []int{3, 4, 5, 6, 7, 8})
+	if rb.HasOverflowed() {
+		t.Errorf("Expect not overflowed")
+	}
+	rb.Resize(2)
+	expect(rb, []int{7, 8})
+	if !rb.HasOverflowed() {
+		t.Errorf("Expect overflowed after elements dropped")
+	}
+	if v, ok := rb.Poll(); !ok || v != 7 {
+		t.Errorf("Expect %d when poll, got %d, %v", 7, v, ok)
+	}
+	rb.Push(9)
+	rb.Push(10)
+	expect(rb, []int{9, 10})
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expect panic for size 0")
+		}
+	}()
+	rb.Resize(0)
+}
+
+func TestRingBufferIterN(t *testing.T) {
+	rb := NewRingBuffer[int](4)
+	for i := range 6 {
+		rb.Push(i)
+	}
+	// buffer: 2 3 4 5
+	for _, n := range []int{-1, 0, 1, 3, 4, 10} {
+		var got []int
+		for v := range rb.IterN(n) {
+			got = append(got, v)
+		}
+		if want := max(min(n, rb.Len()), 0); len(got) != want {
+			t.Errorf("Expect %d elements for n %d, got %v", want, n, got)
+			continue
+		}
+		for i, v := range got {
+			if v != i+2 {
+				t.Errorf("Expect %d at i %d for n %d, got %d", i+2, i, n, v)
+			}
+		}
+	}
+	count := 0
+	for range rb.IterN(3) {
+		count++
+		break
+	}
+	if count != 1 {
+		t.Errorf("Expect iteration to stop after break, got %d", count)
+	}
+}
+
+func TestRingBufferIter2(t *testing.T) {
+	rb := NewRingBuffer[int](4)
+	for i := range 6 {
+		rb.Push(i * 10)
+	}
+	count := 0
+	for k, v := range rb.Iter2() {
+		if k != count || v != rb.Get(k) {
+			t.Errorf("Expect %d, %d when iterate, got %d, %d", count, rb.Get(count), k, v)
+		}
+		count++
+	}
+	if count != rb.Len() {
+		t.Errorf("Expect %d elements, got %d", rb.Len(), count)
+	}
+	count = 0
+	for k, v := range rb.Iter2Reversed() {
+		if want := rb.Len() - 1 - count; k != want || v != rb.Get(want) {
+			t.Errorf("Expect %d, %d when iterate reversed, got %d, %d", want, rb.Get(want), k, v)
+		}
+		count++
+		if count == 2 {
+			break
+		}
+	}
+	if count != 2 {
+		t.Errorf("Expect iteration to stop after break, got %d", count)
+	}
+}
+
+func TestRingBufferDrain(t *testing.T) {
+	rb := NewRingBuffer[int](4)
+	for i := range 6 {
+		rb.Push(i)
+	}
+	// buffer: 2 3 4 5
+	for v := range rb.Drain() {
+		if v != 2 {
+			t.Errorf("Expect %d when drain, got %d", 2, v)
+		}
+		break
+	}
+	for i, v := range []int{3, 4, 5} {
+		if got := rb.Get(i); got != v {
+			t.Errorf("Expect %d at i %d, got %d", v, i, got)
+		}
+	}
+	want := 3
+	for v := range rb.Drain() {
+		if v != want {
+			t.Errorf("Expect %d when drain, got %d", want, v)
+		}
+		want++
+	}
+	if want != 6 {
+		t.Errorf("Expect all elements drained, stopped at %d", want)
+	}
+	if !rb.IsEmpty() {
+		t.Errorf("Expect empty buffer after drain, got length %d", rb.Len())
+	}
+}
+
+func TestRingBufferExtendReversed(t *testing.T) {
+	rb := NewRingBuffer[int](8)
+	rb.Push(1)
+	rb.Push(2)
+	other := NewRingBuffer[int](3)
+	for i := range 5 {
+		other.Push(i + 10)
+	}
+	// other: 12 13 14
+	rb.ExtendReversed(other)
+	expect := func(want []int) {
+		t.Helper()
+		if got := rb.Len(); got != len(want) {
+			t.Fatalf("Expect %d for length, got %d", len(want), got)
+		}
+		for i, v := range want {
+			if got := rb.Get(i); got != v {
+				t.Errorf("Expect %d at i %d, got %d", v, i, got)
+			}
+		}
+	}
+	expect([]int{1, 2, 14, 13, 12})
+	rb.ExtendReversed(rb)
+	expect([]int{14, 13, 12, 12, 13, 14, 2, 1})
+}
+
+func TestRingBufferStablePartition(t *testing.T) {
+	rb := NewRingBuffer[int](7)
+	for _, v := range []int{0, 0, 1, 4, 3, 6, 5, 8, 7} {
+		rb.Push(v)
+	}
+	// buffer: 1 4 3 6 5 8 7, wrapped
+	if got := rb.StablePartition(func(v int) bool { return v%2 == 0 }); got != 3 {
+		t.Errorf("Expect 3 for boundary, got %d", got)
+	}
+	for i, v := range []int{4, 6, 8, 1, 3, 5, 7} {
+		if got := rb.Get(i); got != v {
+			t.Errorf("Expect %d at i %d, got %d", v, i, got)
+		}
+	}
+	if got := rb.StablePartition(func(v int) bool { return v > 100 }); got != 0 {
+		t.Errorf("Expect 0 for boundary, got %d", got)
+	}
+	if got := rb.StablePartition(func(v int) bool { return v < 100 }); got != 7 {
+		t.Errorf("Expect 7 for boundary, got %d", got)
+	}
+	for i, v := range []int{4, 6, 8, 1, 3, 5, 7} {
+		if got := rb.Get(i); got != v {
+			t.Errorf("Expect %d at i %d, got %d", v, i, got)
+		}
+	}
+	if got := rb.StablePartition(func(v int) bool { return v%3 != 0 }); got != 5 {
+		t.Errorf("Expect 5 for boundary, got %d", got)
+	}
+	for i, v := range []int{4, 8, 1, 5, 7, 6, 3} {
+		if got := rb.Get(i); got != v {
+			t.Errorf("Expect %d at i %d, got %d", v, i, got)
+		}
+	}
+	empty := NewRingBuffer[int](3)
+	if got := empty.StablePartition(func(int) bool { return true }); got != 0 {
+		t.Errorf("Expect 0 for boundary of empty buffer, got %d", got)
+	}
+	pred := func(v int) bool { return v%2 == 0 }
+	if allocs := testing.AllocsPerRun(100, func() { rb.StablePartition(pred) }); allocs != 0 {
+		t.Errorf("Expect no allocation, got %v", allocs)
+	}
+}
+
+func TestRingBufferDiscard(t *testing.T) {
+	rb := NewRingBuffer[int](5)
+	for i := range 8 {
+		rb.Push(i)
+	}
+	// buffer: 3 4 5 6 7, wrapped
+	if n := rb.Discard(0); n != 0 {
+		t.Errorf("Expect 0 discarded, got %d", n)
+	}
+	if n := rb.Discard(-1); n != 0 {
+		t.Errorf("Expect 0 discarded, got %d", n)
+	}
+	if n := rb.Discard(3); n != 3 {
+		t.Errorf("Expect 3 discarded, got %d", n)
+	}
+	for i, v := range []int{6, 7} {
+		if got := rb.Get(i); got != v {
+			t.Errorf("Expect %d at i %d, got %d", v, i, got)
+		}
+	}
+	if n := rb.Discard(10); n != 2 {
+		t.Errorf("Expect 2 discarded, got %d", n)
+	}
+	if !rb.IsEmpty() {
+		t.Errorf("Expect empty buffer, got length %d", rb.Len())
+	}
+	for i := range 5 {
+		rb.Push(i)
+	}
+	if n := rb.Discard(5); n != 5 || !rb.IsEmpty() {
+		t.Errorf("Expect full buffer discarded, got %d with length %d", n, rb.Len())
+	}
+	rb.Push(1)
+	if got, ok := rb.Peek(); !ok || got != 1 {
+		t.Errorf("Expect %d when peek, got %d", 1, got)
+	}
+}
+
+func TestMapField(t *testing.T) {
+	type record struct {
+		name  string
+		count int
+	}
+	rb := NewRingBuffer[record](3)
+	for i, name := range []string{"a", "b", "c", "d"} {
+		rb.Push(record{name, i * 10})
+	}
+	counts := MapField(rb, func(r record) int { return r.count })
+	for i, v := range []int{10, 20, 30} {
+		if counts[i] != v {
+			t.Errorf("Expect %d at i %d, got %d", v, i, counts[i])
+		}
+	}
+	if got := MapField(NewRingBuffer[record](1), func(r record) string { return r.name }); len(got) != 0 {
+		t.Errorf("Expect empty result for empty buffer, got %v", got)
+	}
+}
+
+func TestRingBufferLast(t *testing.T) {
+	rb := NewRingBuffer[int](5)
+	if got := rb.Last(3); len(got) != 0 {
+		t.Errorf("Expect empty result for empty buffer, got %v", got)
+	}
+	for i := range 8 {
+		rb.Push(i)
+	}
+	// buffer: 3 4 5 6 7, wrapped
+	for _, n := range []int{-1, 0, 1, 3, 5, 9} {
+		got := rb.Last(n)
+		want := max(min(n, 5), 0)
+		if len(got) != want {
+			t.Errorf("Expect %d elements for n %d, got %v", want, n, got)
+			continue
+		}
+		for i, v := range got {
+			if w := 8 - want + i; v != w {
+				t.Errorf("Expect %d at i %d for n %d, got %d", w, i, n, v)
+			}
+		}
+	}
+}
+
+func TestRingBufferCopyTo(t *testing.T) {
+	rb := NewRingBuffer[int](5)
+	dst := make([]int, 4)
+	if n := rb.CopyTo(dst); n != 0 {
+		t.Errorf("Expect 0 copied for empty buffer, got %d", n)
+	}
+	for i := range 8 {
+		rb.Push(i)
+	}
+	// buffer: 3 4 5 6 7, wrapped
+	if n := rb.CopyTo(dst); n != 4 {
+		t.Errorf("Expect 4 copied, got %d", n)
+	}
+	for i, v := range []int{3, 4, 5, 6} {
+		if dst[i] != v {
+			t.Errorf("Expect %d at i %d of dst, got %d", v, i, dst[i])
+		}
+	}
+	dst = make([]int, 10)
+	if n := rb.CopyTo(dst); n != 5 {
+		t.Errorf("Expect 5 copied, got %d", n)
+	}
+	if dst[4] != 7 || dst[5] != 0 {
+		t.Errorf("Expect only 5 elements copied, got %v", dst)
+	}
+	if got := rb.Len(); got != 5 {
+		t.Errorf("Expect buffer unchanged, got length %d", got)
+	}
+	if allocs := testing.AllocsPerRun(100, func() { rb.CopyTo(dst) }); allocs != 0 {
+		t.Errorf("Expect no allocation, got %v", allocs)
+	}
+}
+
+func TestRingBufferForEachWithPrev(t *testing.T) {
+	rb := NewRingBuffer[int](5)
+	rb.ForEachWithPrev(func(prev, cur int, hasPrev bool) bool {
+		t.Errorf("Expect no call for empty buffer")
+		return true
+	})
+	for i := range 8 {
+		rb.Push(i * i)
+	}
+	// buffer: 9 16 25 36 49, wrapped
+	k := 0
+	rb.ForEachWithPrev(func(prev, cur int, hasPrev bool) bool {
+		if got := rb.Get(k); cur != got {
+			t.Errorf("Expect %d for cur at k %d, got %d", got, k, cur)
+		}
+		if hasPrev != (k > 0) {
+			t.Errorf("Expect hasPrev %v at k %d, got %v", k > 0, k, hasPrev)
+		}
+		if k > 0 {
+			if got := rb.Get(k - 1); prev != got {
+				t.Errorf("Expect %d for prev at k %d, got %d", got, k, prev)
+			}
+		} else if prev != 0 {
+			t.Errorf("Expect zero prev at k 0, got %d", prev)
+		}
+		k++
+		return true
+	})
+	if k != 5 {
+		t.Errorf("Expect 5 calls, got %d", k)
+	}
+	k = 0
+	rb.ForEachWithPrev(func(prev, cur int, hasPrev bool) bool {
+		k++
+		return cur < 25
+	})
+	if k != 3 {
+		t.Errorf("Expect break after 3 calls, got %d", k)
+	}
+}
+
+func TestRingBufferRemoveFunc(t *testing.T) {
+	rb := NewRingBuffer[int](6)
+	if n := rb.RemoveFunc(func(int) bool { return false }); n != 0 {
+		t.Errorf("Expect 0 removed for empty buffer, got %d", n)
+	}
+	for i := range 9 {
+		rb.Push(i)
+	}
+	// buffer: 3 4 5 6 7 8, wrapped
+	if n := rb.RemoveFunc(func(v int) bool { return v%3 != 0 }); n != 2 {
+		t.Errorf("Expect 2 removed, got %d", n)
+	}
+	want := []int{4, 5, 7, 8}
+	if got := rb.Len(); got != len(want) {
+		t.Fatalf("Expect %d for length, got %d", len(want), got)
+	}
+	for i, v := range want {
+		if got := rb.Get(i); got != v {
+			t.Errorf("Expect %d at i %d, got %d", v, i, got)
+		}
+	}
+	rb.Push(9)
+	rb.Push(10)
+	rb.Push(11)
+	if got, want := rb.Get(0), 5; got != want {
+		t.Errorf("Expect %d at i 0 after push, got %d", want, got)
+	}
+	if n := rb.RemoveFunc(func(int) bool { return false }); n != 6 {
+		t.Errorf("Expect 6 removed, got %d", n)
+	}
+	if !rb.IsEmpty() {
+		t.Errorf("Expect buffer empty")
+	}
+}
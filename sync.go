@@ -0,0 +1,120 @@
+// Ring buffer
+// Copyright (C) 2025  Kevin Z <zyxkad@gmail.com>
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package ringbuf
+
+import (
+	"iter"
+	"slices"
+	"sync"
+)
+
+// SyncRingBuffer is a ring buffer which is safe for concurrent use
+type SyncRingBuffer[T any] struct {
+	mux sync.RWMutex
+	buf *RingBuffer[T]
+}
+
+func NewSyncRingBuffer[T any](size int) *SyncRingBuffer[T] {
+	return &SyncRingBuffer[T]{
+		buf: NewRingBuffer[T](size),
+	}
+}
+
+// Push puts an element into the ring buffer
+// It will overwrite the earliest element if there is no space avaliable
+func (r *SyncRingBuffer[T]) Push(v T) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.buf.Push(v)
+}
+
+// PushSafe puts an element into the ring buffer only if there is space avaliable
+func (r *SyncRingBuffer[T]) PushSafe(v T) bool {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	return r.buf.PushSafe(v)
+}
+
+// Poll removes the earliest pushed element from the ring buffer
+func (r *SyncRingBuffer[T]) Poll() (v T, ok bool) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	return r.buf.Poll()
+}
+
+// Peek returns the earliest pushed element without removing it
+func (r *SyncRingBuffer[T]) Peek() (v T, ok bool) {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+	return r.buf.Peek()
+}
+
+// PeekBack returns the last pushed element without removing it
+func (r *SyncRingBuffer[T]) PeekBack() (v T, ok bool) {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+	return r.buf.PeekBack()
+}
+
+// Len returns the used space of the buffer
+func (r *SyncRingBuffer[T]) Len() int {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+	return r.buf.Len()
+}
+
+// Cap returns the total space of the buffer
+func (r *SyncRingBuffer[T]) Cap() int {
+	return r.buf.Cap()
+}
+
+// Clear set ring buffer's length to zero
+func (r *SyncRingBuffer[T]) Clear() {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.buf.Clear()
+}
+
+// snapshot returns a copy of the elements from first to last
+func (r *SyncRingBuffer[T]) snapshot() []T {
+	r.mux.RLock()
+	defer r.mux.RUnlock()
+	return r.buf.ToSlice()
+}
+
+// Iter returns an iterator of the buffer that iterate from first to last
+// The elements are copied when the iteration starts, so the lock is not held while yielding
+func (r *SyncRingBuffer[T]) Iter() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, v := range r.snapshot() {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// IterReversed returns an iterator of the buffer that iterate from last to first
+// The elements are copied when the iteration starts, so the lock is not held while yielding
+func (r *SyncRingBuffer[T]) IterReversed() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, v := range slices.Backward(r.snapshot()) {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
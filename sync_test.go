@@ -0,0 +1,113 @@
+// Ring buffer
+// Copyright (C) 2025  Kevin Z <zyxkad@gmail.com>
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package ringbuf_test
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+
+	. "github.com/kmcsr/go-ringbuf"
+)
+
+func TestSyncRingBuffer(t *testing.T) {
+	rb := NewSyncRingBuffer[int](4)
+	for i := range 6 {
+		rb.Push(i)
+	}
+	if rb.PushSafe(6) {
+		t.Errorf("Expect push fail when buffer is full")
+	}
+	if got, ok := rb.Peek(); !ok || got != 2 {
+		t.Errorf("Expect %d when peek, got %d, %v", 2, got, ok)
+	}
+	if got, ok := rb.PeekBack(); !ok || got != 5 {
+		t.Errorf("Expect %d when peek back, got %d, %v", 5, got, ok)
+	}
+	i := 2
+	for v := range rb.Iter() {
+		if v != i {
+			t.Errorf("Expect %d when iterate, got %d", i, v)
+		}
+		rb.Push(v) // must not dead lock
+		i++
+	}
+	i = 5
+	for v := range rb.IterReversed() {
+		if v != i {
+			t.Errorf("Expect %d when iterate reversed, got %d", i, v)
+		}
+		i--
+	}
+	rb.Clear()
+	if got := rb.Len(); got != 0 {
+		t.Errorf("Expect 0 for length, got %d", got)
+	}
+}
+
+func TestSyncRingBufferConcurrent(t *testing.T) {
+	const (
+		workers = 4
+		count   = 1000
+	)
+	rb := NewSyncRingBuffer[int](64)
+	var (
+		wg     sync.WaitGroup
+		mux    sync.Mutex
+		polled int
+	)
+	for range workers {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			for i := range count {
+				for !rb.PushSafe(i) {
+					runtime.Gosched()
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			n := 0
+			for n < count {
+				if _, ok := rb.Poll(); ok {
+					n++
+				} else {
+					runtime.Gosched()
+				}
+			}
+			mux.Lock()
+			polled += n
+			mux.Unlock()
+		}()
+		go func() {
+			defer wg.Done()
+			for range count / 10 {
+				rb.Peek()
+				rb.Len()
+				for range rb.Iter() {
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if polled != workers*count {
+		t.Errorf("Expect %d polled, got %d", workers*count, polled)
+	}
+	if got := rb.Len(); got != 0 {
+		t.Errorf("Expect 0 for length, got %d", got)
+	}
+}